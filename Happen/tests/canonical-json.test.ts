@@ -0,0 +1,33 @@
+/**
+ * Pins down the exact bytes that event integrity hashes are computed over.
+ *
+ * canonicalStringify sorts keys, but JavaScript still lists integer-like keys
+ * first in numeric order, and numbers and string escapes follow
+ * JSON.stringify. calculateEventHash blanks context.integrity before hashing.
+ * Changing any of this invalidates every stored signature. The Go integrity
+ * check in tui recomputes the same vectors from fixtures/canonical-json.json,
+ * so a failure here means updating both sides together.
+ */
+
+import { createHash } from 'crypto';
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import { canonicalStringify } from '../src/utils/canonicalStringify';
+import { EventIntegrityManager } from '../src/integrity';
+
+const fixture = JSON.parse(
+  readFileSync(join(__dirname, 'fixtures', 'canonical-json.json'), 'utf8')
+);
+
+describe('Canonical JSON conformance', () => {
+  it.each(fixture.canonicalStringify)('canonicalStringify: $name', ({ input, expected, sha256 }: any) => {
+    const canonical = canonicalStringify(JSON.parse(input));
+    expect(canonical).toBe(expected);
+    expect(createHash('sha256').update(canonical, 'utf8').digest('hex')).toBe(sha256);
+  });
+
+  it.each(fixture.calculateEventHash)('calculateEventHash: $name', async ({ event, expected }: any) => {
+    const manager = new EventIntegrityManager();
+    expect(await manager.calculateEventHash(JSON.parse(event))).toBe(expected);
+  });
+});
@@ -0,0 +1,91 @@
+/**
+ * Conformance tests for subject routing and event-type patterns.
+ *
+ * The cases live in fixtures/subject-conformance.json so that other
+ * implementations (the Go port in tui/happen) can be checked against the
+ * same expectations.
+ */
+
+import { readFileSync } from 'fs';
+import { join } from 'path';
+import {
+  SUBJECT_PATTERNS,
+  SubjectBuilder,
+  SubjectParser,
+  EventRouter,
+  SubjectValidator,
+} from '../src/transport/subjects';
+import { matchesPattern } from '../src/core/pattern-matcher';
+import { createMatcher } from '../src/patterns';
+import { createEvent } from '../src/events';
+
+const fixture = JSON.parse(
+  readFileSync(join(__dirname, 'fixtures', 'subject-conformance.json'), 'utf8')
+);
+
+describe('Subject conformance', () => {
+  it('exposes the same subject prefixes', () => {
+    expect(SUBJECT_PATTERNS).toEqual(fixture.subjectPatterns);
+  });
+
+  it.each(fixture.build)('SubjectBuilder.$fn($args)', ({ fn, args, expected }: any) => {
+    expect((SubjectBuilder as any)[fn](...args)).toBe(expected);
+  });
+
+  it.each(fixture.parseEventSubject)('parseEventSubject($subject)', ({ subject, expected }: any) => {
+    expect(SubjectParser.parseEventSubject(subject)).toEqual(expected);
+  });
+
+  it.each(fixture.parseStateSubject)('parseStateSubject($subject)', ({ subject, expected }: any) => {
+    expect(SubjectParser.parseStateSubject(subject)).toEqual(expected);
+  });
+
+  it.each(fixture.isSystemSubject)('isSystemSubject($subject)', ({ subject, expected }: any) => {
+    expect(SubjectParser.isSystemSubject(subject)).toBe(expected);
+  });
+
+  it.each(fixture.isAdminSubject)('isAdminSubject($subject)', ({ subject, expected }: any) => {
+    expect(SubjectParser.isAdminSubject(subject)).toBe(expected);
+  });
+
+  it.each(fixture.subjectForEvent)('getSubjectForEvent($type, $target)', ({ type, sender, target, expected }: any) => {
+    const event = createEvent(type, {}, undefined, sender);
+    expect(EventRouter.getSubjectForEvent(event, target ?? undefined)).toBe(expected);
+  });
+
+  it.each(fixture.subscriptionSubjects)('getSubscriptionSubjects($nodeId)', ({ nodeId, expected }: any) => {
+    expect(EventRouter.getSubscriptionSubjects(nodeId)).toEqual(expected);
+  });
+
+  it.each(fixture.filterSubjects)('filterSubjects with prefix $allowPrefix', ({ subjects, allowPrefix, expected }: any) => {
+    const config = allowPrefix === null
+      ? {}
+      : { subjectFilter: (subject: string) => subject.startsWith(allowPrefix) };
+    expect(EventRouter.filterSubjects(subjects, config)).toEqual(expected);
+  });
+
+  it.each(fixture.isValidSubject)('isValidSubject($subject)', ({ subject, expected }: any) => {
+    expect(SubjectValidator.isValidSubject(subject)).toBe(expected);
+  });
+
+  it.each(fixture.isValidWildcardSubject)('isValidWildcardSubject($subject)', ({ subject, expected }: any) => {
+    expect(SubjectValidator.isValidWildcardSubject(subject)).toBe(expected);
+  });
+
+  it.each(fixture.matchesSubjectPattern)('SubjectValidator.matchesPattern($subject, $pattern)', ({ subject, pattern, expected }: any) => {
+    expect(SubjectValidator.matchesPattern(subject, pattern)).toBe(expected);
+  });
+});
+
+describe('Event pattern conformance', () => {
+  it.each(fixture.matchesPattern)('matchesPattern($pattern, $eventType)', ({ pattern, eventType, separator, expected }: any) => {
+    const matched = separator === undefined
+      ? matchesPattern(pattern, eventType)
+      : matchesPattern(pattern, eventType, separator);
+    expect(matched).toBe(expected);
+  });
+
+  it.each(fixture.createMatcher)('createMatcher($pattern)($eventType)', ({ pattern, eventType, expected }: any) => {
+    expect(createMatcher(pattern)(eventType)).toBe(expected);
+  });
+});
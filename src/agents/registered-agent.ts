@@ -10,6 +10,9 @@ export class RegisteredAgent extends AutonomousAgent {
   private successfulTasks = 0;
   private startTime = Date.now();
 
+  // Called after every task, finished or failed, with how long it took
+  onTaskCompleted?: (report: { agentId: string; task: string; duration: number }) => void;
+
   constructor(
     multiplexer: ClaudeMultiplexer,
     nodeId: string,
@@ -73,8 +76,10 @@ export class RegisteredAgent extends AutonomousAgent {
       const result = await super.executeTask(task, context);
       
       // Update metrics
+      const duration = Date.now() - startTime;
       this.tasksCompleted++;
-      this.totalResponseTime += (Date.now() - startTime);
+      this.totalResponseTime += duration;
+      this.onTaskCompleted?.({ agentId: this.agentId, task, duration });
       if (result.success) {
         this.successfulTasks++;
       }
@@ -87,6 +92,7 @@ export class RegisteredAgent extends AutonomousAgent {
       
       return result;
     } catch (error) {
+      this.onTaskCompleted?.({ agentId: this.agentId, task, duration: Date.now() - startTime });
       // Update status to error
       await this.updateStatus('error');
       throw error;
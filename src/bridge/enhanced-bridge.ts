@@ -1,6 +1,7 @@
 import { WebSocketServer, WebSocket } from 'ws';
 import { EventEmitter } from 'events';
-import { EnhancedCabal } from '../enhanced-cabal.js';
+import { getTemporalStore } from '@happen/core';
+import { ControlCenterCabal } from '../control-center-cabal.js';
 import { HumanNotification } from '../enhanced-cabal.js';
 
 export interface TUINotification {
@@ -10,11 +11,14 @@ export interface TUINotification {
     notificationLevel: 'normal' | 'notification' | 'critical';
     pendingRequests: number;
     message?: string;
+    requestId?: string;
+    correlationId?: string;
+    causationId?: string;
   };
 }
 
 export interface BridgeMessage {
-  type: 'agent:spawn' | 'agent:kill' | 'agent:message' | 'agent:list' | 'stats' | 'human:response' | 'agent:notification';
+  type: 'agent:spawn' | 'agent:kill' | 'agent:message' | 'agent:response' | 'agent:list' | 'stats' | 'human:request' | 'human:response' | 'agent:notification' | 'agent:background' | 'event:captured' | 'workflow:update' | 'workflow:step' | 'workflow:submit' | 'registry:update' | 'agent:heartbeat' | 'agent:policy' | 'agent:decision' | 'task:submit' | 'task:accepted' | 'task:rejected' | 'task:completed' | 'state:history';
   payload: any;
   id?: string;
 }
@@ -22,15 +26,21 @@ export interface BridgeMessage {
 export class EnhancedWebSocketBridge extends EventEmitter {
   private wss: WebSocketServer;
   private clients: Set<WebSocket> = new Set();
-  private cabal: EnhancedCabal;
+  private cabal: ControlCenterCabal;
   private agentNotificationStates: Map<string, any> = new Map();
+  private statsInterval?: NodeJS.Timeout;
 
   constructor(port: number = 8080) {
     super();
     this.wss = new WebSocketServer({ port });
-    this.cabal = new EnhancedCabal();
+    this.cabal = new ControlCenterCabal();
     this.setupServer();
     this.setupNotificationHandlers();
+
+    // The control center charts and alerts on these samples
+    this.statsInterval = setInterval(() => {
+      this.broadcast({ type: 'stats', payload: this.cabal.getSystemStats() });
+    }, 5000);
   }
 
   private setupServer() {
@@ -41,8 +51,15 @@ export class EnhancedWebSocketBridge extends EventEmitter {
       // Send initial state
       this.sendToClient(ws, {
         type: 'stats',
-        payload: this.cabal.getSystemStatus()
+        payload: this.cabal.getSystemStats()
+      });
+      this.sendToClient(ws, {
+        type: 'agent:list',
+        payload: { agents: this.cabal.getRegisteredAgents() }
       });
+      for (const policy of this.cabal.getAgentPolicies()) {
+        this.sendToClient(ws, { type: 'agent:policy', payload: this.clientPolicy(policy) });
+      }
 
       ws.on('message', async (data) => {
         try {
@@ -90,9 +107,21 @@ export class EnhancedWebSocketBridge extends EventEmitter {
             agentId,
             notificationLevel: level,
             pendingRequests: currentState.pendingRequests,
-            message: this.formatNotificationMessage(notification)
+            message: this.formatNotificationMessage(notification),
+            requestId: notification.content.id,
+            correlationId: notification.content.context?.correlationId,
+            causationId: notification.content.context?.causationId
           }
         } as TUINotification);
+
+        // Coordinator requests carry the ID a human:response must answer
+        if (notification.content.id) {
+          const { id, type, priority, from, context, options, timestamp, timeout } = notification.content;
+          this.broadcast({
+            type: 'human:request',
+            payload: { id, type, priority, from, context, options, timestamp, timeout }
+          });
+        }
       }
     });
 
@@ -109,27 +138,57 @@ export class EnhancedWebSocketBridge extends EventEmitter {
       });
     });
 
-    // Monitor background activity
-    let activityBuffer: any[] = [];
+    // Forward agent-to-agent activity on its own stream so it stays out of the conversation
     this.cabal.on('agent:background', (activity) => {
-      activityBuffer.push(activity);
-      
-      // Send summary every 5 activities
-      if (activityBuffer.length >= 5) {
-        const summary = this.summarizeActivity(activityBuffer);
-        activityBuffer = [];
-        
-        // Send low-priority notification
-        this.broadcast({
-          type: 'agent:notification',
-          payload: {
-            agentId: activity.from,
-            notificationLevel: 'normal',
-            pendingRequests: 0,
-            message: summary
-          }
-        } as TUINotification);
-      }
+      this.broadcast({
+        type: 'agent:background',
+        payload: {
+          from: activity.from,
+          to: activity.to,
+          type: activity.type,
+          content: activity.content,
+          correlationId: activity.correlationId ?? activity.content?.correlationId,
+          causationId: activity.causationId ?? activity.content?.causationId,
+          timestamp: activity.timestamp ?? Date.now()
+        }
+      });
+    });
+
+    // Captured traffic with its causal IDs feeds the control center's Events, Topology and Traces
+    this.cabal.onEvent((event) => {
+      this.broadcast({ type: 'event:captured', payload: event });
+    });
+
+    // Registry changes keep the control center's agent list current
+    this.cabal.on('registry:update', (update) => {
+      this.broadcast({ type: 'registry:update', payload: update });
+    });
+
+    // Heartbeats let the control center tell live agents from silent ones
+    this.cabal.on('agent:heartbeat', (heartbeat) => {
+      this.broadcast({ type: 'agent:heartbeat', payload: heartbeat });
+    });
+
+    // Decisions feed the control center's policy preview
+    this.cabal.on('agent:decision', (decision) => {
+      this.broadcast({
+        type: 'agent:decision',
+        payload: { ...decision, agentId: this.registryId(decision.agentId) }
+      });
+    });
+
+    // Task timings feed the control center's latency percentiles
+    this.cabal.on('task:completed', (report) => {
+      this.broadcast({ type: 'task:completed', payload: report });
+    });
+
+    // Workflow progress for the control center's Workflows tab
+    this.cabal.on('workflow:update', (workflow) => {
+      this.broadcast({ type: 'workflow:update', payload: workflow });
+    });
+
+    this.cabal.on('workflow:step', (update) => {
+      this.broadcast({ type: 'workflow:step', payload: update });
     });
   }
 
@@ -157,12 +216,6 @@ export class EnhancedWebSocketBridge extends EventEmitter {
     return 'Notification';
   }
 
-  private summarizeActivity(activities: any[]): string {
-    const types = activities.map(a => a.type);
-    const uniqueTypes = [...new Set(types)];
-    return `Completed: ${uniqueTypes.join(', ')} (${activities.length} total)`;
-  }
-
   private async handleMessage(ws: WebSocket, msg: BridgeMessage) {
     switch (msg.type) {
       case 'agent:spawn':
@@ -172,22 +225,40 @@ export class EnhancedWebSocketBridge extends EventEmitter {
           type: 'analyst',
           autonomyLevel: 'supervised'
         };
-        await this.cabal.spawnSpecializedAgent(role);
+        await this.cabal.spawnRegisteredAgent(role);
         break;
 
       case 'agent:kill':
         // Not implemented in EnhancedCabal yet
         break;
 
-      case 'agent:message':
-        // Route message to specific agent
+      case 'agent:message': {
+        // Route message to specific agent; failures are answered too so the TUI never waits forever
         const agent = this.cabal['agents'].get(msg.payload.agentId);
-        if (agent) {
-          await agent.executeTask('user-message', {
-            message: msg.payload.content
-          });
+        let content: any;
+        if (!agent) {
+          content = `Error: unknown agent ${msg.payload.agentId}`;
+        } else {
+          try {
+            // The correlation ID travels with the task so the TUI can thread everything it triggers
+            content = await agent.executeTask('user-message', {
+              message: msg.payload.content,
+              correlationId: msg.payload.correlationId
+            });
+          } catch (e: any) {
+            content = `Error: ${e.message}`;
+          }
         }
+        this.broadcast({
+          type: 'agent:response',
+          payload: {
+            agentId: msg.payload.agentId,
+            content,
+            correlationId: msg.payload.correlationId
+          }
+        });
         break;
+      }
 
       case 'human:response':
         // Human responding to a request
@@ -211,15 +282,109 @@ export class EnhancedWebSocketBridge extends EventEmitter {
         }
         break;
 
+      case 'agent:list':
+        this.sendToClient(ws, {
+          type: 'agent:list',
+          payload: { agents: this.cabal.getRegisteredAgents() }
+        });
+        break;
+
+      case 'agent:policy': {
+        // The control center names agents by registry ID; the coordinator keys policies by node ID
+        const target = this.cabal.getAgentByRegistryId(msg.payload.agentId);
+        if (!target) {
+          // Answered on agent:policy so the control center stops waiting for the echo
+          this.sendToClient(ws, { type: 'agent:policy', payload: { agentId: msg.payload.agentId, error: `unknown agent ${msg.payload.agentId}` } });
+          break;
+        }
+        const { autonomyLevel, requiresApprovalFor, notifyHumanFor } = msg.payload;
+        const policy = this.cabal.setAgentPolicy(target['nodeId'], { autonomyLevel, requiresApprovalFor, notifyHumanFor });
+        this.broadcast({ type: 'agent:policy', payload: this.clientPolicy(policy) });
+        break;
+      }
+
+      case 'task:submit':
+        await this.submitTask(ws, msg.payload);
+        break;
+
+      case 'state:history': {
+        // Snapshots are recorded per node by nodes with temporal state enabled; failures are
+        // answered on state:history so the control center stops waiting
+        const agentId = msg.payload.agentId;
+        const target = this.cabal.getAgentByRegistryId(agentId);
+        if (!target) {
+          this.sendToClient(ws, { type: 'state:history', payload: { agentId, error: `unknown agent ${agentId}` } });
+          break;
+        }
+        try {
+          const snapshots = await getTemporalStore(target['nodeId']).when(() => true, (found) => found);
+          this.sendToClient(ws, { type: 'state:history', payload: { agentId, snapshots } });
+        } catch (e: any) {
+          this.sendToClient(ws, { type: 'state:history', payload: { agentId, error: e.message } });
+        }
+        break;
+      }
+
+      case 'workflow:submit':
+        // Runs in the background; progress arrives as workflow:update and workflow:step
+        this.cabal.runWorkflow(msg.payload.id, msg.payload.definition).catch((e) => {
+          this.broadcast({
+            type: 'workflow:update',
+            payload: { id: msg.payload.id, name: msg.payload.definition?.name, status: 'failed' }
+          });
+          this.sendError(ws, e.message);
+        });
+        break;
+
       case 'stats':
         this.sendToClient(ws, {
           type: 'stats',
-          payload: this.cabal.getSystemStatus()
+          payload: this.cabal.getSystemStats()
         });
         break;
     }
   }
 
+  // A task from the control center goes to the agent it picked, or else to the registry's best
+  // match. The client only logs the task once it is accepted here.
+  private async submitTask(ws: WebSocket, task: any) {
+    const { id, description, capabilities = [], preferredType, agentId } = task;
+    const target = agentId || await this.cabal.findBestAgentForTask({
+      type: preferredType || undefined,
+      capabilities,
+      urgency: 'high'
+    });
+    const agent = target ? this.cabal.getAgentByRegistryId(target) : undefined;
+    if (!agent) {
+      this.sendToClient(ws, {
+        type: 'task:rejected',
+        payload: {
+          id,
+          description,
+          error: agentId ? `unknown agent ${agentId}` : 'no agent has the required capabilities'
+        }
+      });
+      return;
+    }
+
+    this.sendToClient(ws, {
+      type: 'task:accepted',
+      payload: { id, agentId: target, description }
+    });
+    agent.executeTask(description, { taskId: id, capabilities }).catch((e) => {
+      this.sendError(ws, `task ${id}: ${e.message}`);
+    });
+  }
+
+  private registryId(nodeId: string): string {
+    const agent = this.cabal['agents'].get(nodeId);
+    return (agent && agent['agentId']) || nodeId;
+  }
+
+  private clientPolicy(policy: any) {
+    return { ...policy, agentId: this.registryId(policy.agentId) };
+  }
+
   private sendToClient(ws: WebSocket, msg: any) {
     if (ws.readyState === WebSocket.OPEN) {
       ws.send(JSON.stringify(msg));
@@ -253,13 +418,14 @@ export class EnhancedWebSocketBridge extends EventEmitter {
     ];
 
     for (const role of roles) {
-      await this.cabal.spawnSpecializedAgent(role);
+      await this.cabal.spawnRegisteredAgent(role);
     }
     
     console.log('Initial agent swarm created');
   }
 
   async shutdown() {
+    clearInterval(this.statsInterval);
     await this.cabal.shutdown();
     this.wss.close();
   }
@@ -1,6 +1,7 @@
-import { EnhancedCabal } from './enhanced-cabal.js';
+import { EnhancedCabal, WorkflowStepDefinition } from './enhanced-cabal.js';
 import { AgentRegistry } from './registry/agent-registry.js';
 import { RegisteredAgent } from './agents/registered-agent.js';
+import { AutonomousAgent } from './agents/autonomous-agent.js';
 import { EventEmitter } from 'events';
 
 export interface ControlCenterEvent {
@@ -9,6 +10,11 @@ export interface ControlCenterEvent {
   from: string;
   to: string;
   data: any;
+  metadata: {
+    id: string;
+    correlationId: string;
+    causationId?: string;
+  };
 }
 
 export class ControlCenterCabal extends EnhancedCabal {
@@ -16,6 +22,8 @@ export class ControlCenterCabal extends EnhancedCabal {
   private eventStream: ControlCenterEvent[] = [];
   private eventEmitter = new EventEmitter();
   private readonly MAX_EVENTS = 1000;
+  // Last event delivered to each agent, taken as the cause of what it sends next
+  private lastReceived: Map<string, ControlCenterEvent['metadata']> = new Map();
 
   constructor(maxAgents: number = 10) {
     super(maxAgents);
@@ -46,18 +54,37 @@ export class ControlCenterCabal extends EnhancedCabal {
         agents: this.registry.getAllAgents()
       });
     });
+
+    this.registry.on('agent:heartbeat', (event) => {
+      this.emit('agent:heartbeat', {
+        agentId: event.agentId,
+        timestamp: event.timestamp
+      });
+    });
   }
 
   private setupEventCapture() {
     // Capture all Happen events for the event stream
     const captureEvent = (type: string, data: any) => {
+      const from = data.from || data.agentId || 'system';
+      const to = data.to || 'broadcast';
+      const id = crypto.randomUUID();
+      const cause = this.lastReceived.get(from);
       const event: ControlCenterEvent = {
         timestamp: Date.now(),
         type,
-        from: data.from || data.agentId || 'system',
-        to: data.to || 'broadcast',
-        data
+        from,
+        to,
+        data,
+        metadata: {
+          id,
+          correlationId: data.correlationId ?? data.content?.correlationId ?? data.requestId ?? cause?.correlationId ?? id,
+          causationId: data.causationId ?? data.content?.causationId ?? cause?.id
+        }
       };
+      if (to !== 'broadcast') {
+        this.lastReceived.set(to, event.metadata);
+      }
 
       this.eventStream.unshift(event);
       if (this.eventStream.length > this.MAX_EVENTS) {
@@ -98,6 +125,10 @@ export class ControlCenterCabal extends EnhancedCabal {
       config
     );
 
+    agent.onTaskCompleted = (report) => {
+      this.emit('task:completed', { ...report, timestamp: Date.now() });
+    };
+
     await agent.initialize();
     this.agents.set(agent.nodeId, agent);
 
@@ -203,6 +234,45 @@ export class ControlCenterCabal extends EnhancedCabal {
     };
   }
 
+  // Shaped like the control center's SystemStats. Response time and success rate are only
+  // reported once a task has finished, so an idle swarm does not read as failing.
+  getSystemStats() {
+    const agents = this.registry.getAllAgents();
+    const tasksCompleted = agents.reduce((sum, a) => sum + a.performance.tasksCompleted, 0);
+    const stats: Record<string, number> = {
+      totalAgents: agents.length,
+      onlineAgents: agents.filter(a => a.status === 'online' || a.status === 'busy').length,
+      tasksCompleted,
+      eventsPerMinute: this.eventStream.filter(e => e.timestamp > Date.now() - 60000).length
+    };
+    if (tasksCompleted > 0) {
+      // Weighted by each agent's task count
+      stats.avgResponseTime = agents.reduce((sum, a) => sum + a.performance.avgResponseTime * a.performance.tasksCompleted, 0) / tasksCompleted;
+      stats.successRate = agents.reduce((sum, a) => sum + a.performance.successRate * a.performance.tasksCompleted, 0) / tasksCompleted;
+    }
+    return stats;
+  }
+
+  getRegisteredAgents() {
+    return this.registry.getAllAgents();
+  }
+
+  // Agents are keyed by node ID here; the registry and its clients know them by registry ID
+  getAgentByRegistryId(agentId: string): AutonomousAgent | undefined {
+    const profile = this.registry.getAgent(agentId);
+    return this.agents.get(profile ? profile.name : agentId);
+  }
+
+  // Workflow steps are matched on the registry's types and capabilities
+  protected findAgentForStep(step: WorkflowStepDefinition): AutonomousAgent | undefined {
+    const profile = this.registry.findBestAgent({
+      type: step.role,
+      capabilities: step.capability ? [step.capability] : undefined,
+      preferOnline: true
+    });
+    return profile ? this.agents.get(profile.name) : undefined;
+  }
+
   // Get live event stream
   getEventStream(limit: number = 100): ControlCenterEvent[] {
     return this.eventStream.slice(0, limit);
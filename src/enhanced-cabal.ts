@@ -1,6 +1,6 @@
 import { ClaudeMultiplexer } from './multiplex/multiplexer.js';
 import { AutonomousAgent, AutonomousConfig } from './agents/autonomous-agent.js';
-import { HumanInTheLoopCoordinator } from './human-loop/coordinator.js';
+import { HumanInTheLoopCoordinator, AgentPolicy } from './human-loop/coordinator.js';
 import { AsyncRouter } from './multiplex/async-router.js';
 import { StreamSplitter } from './multiplex/stream-splitter.js';
 import { EventEmitter } from 'events';
@@ -20,10 +20,30 @@ export interface HumanNotification {
   requiresResponse: boolean;
 }
 
+export interface WorkflowStepDefinition {
+  id: string;
+  name?: string;
+  role?: string;
+  capability?: string;
+  task?: string;
+  dependsOn?: string[];
+  inputs?: Record<string, string>;
+  outputs?: string[];
+  requiresApproval?: boolean;
+  approvalPrompt?: string;
+}
+
+export interface WorkflowDefinition {
+  name: string;
+  description?: string;
+  inputs?: Record<string, string>;
+  steps: WorkflowStepDefinition[];
+}
+
 export class EnhancedCabal extends EventEmitter {
-  private multiplexer: ClaudeMultiplexer;
-  private agents: Map<string, AutonomousAgent> = new Map();
-  private coordinator: HumanInTheLoopCoordinator;
+  protected multiplexer: ClaudeMultiplexer;
+  protected agents: Map<string, AutonomousAgent> = new Map();
+  protected coordinator: HumanInTheLoopCoordinator;
   private router: AsyncRouter;
   private splitter: StreamSplitter;
   private backgroundActivity: any[] = [];
@@ -51,6 +71,7 @@ export class EnhancedCabal extends EventEmitter {
     // Monitor background agent activity
     this.coordinator.on('agent:background', (activity) => {
       this.backgroundActivity.push(activity);
+      this.emit('agent:background', activity);
       // Emit summary every 10 activities
       if (this.backgroundActivity.length % 10 === 0) {
         this.emit('human:attention', {
@@ -65,6 +86,22 @@ export class EnhancedCabal extends EventEmitter {
       }
     });
 
+    // Peer messages are background activity too
+    this.coordinator.on('communication:log', (log) => {
+      this.emit('agent:background', {
+        from: log.from,
+        to: log.to,
+        type: 'communication',
+        content: log.message.content,
+        timestamp: log.timestamp
+      });
+    });
+
+    // Decisions pass through for policy previews
+    this.coordinator.on('agent:decision', (decision) => {
+      this.emit('agent:decision', decision);
+    });
+
     // Set up autonomous agent coordination routes
     this.router.addRoute('coordinate', {
       pattern: /coordinate:/,
@@ -108,7 +145,7 @@ export class EnhancedCabal extends EventEmitter {
     return agent;
   }
 
-  private getApprovalTasksForRole(roleType: string): string[] {
+  protected getApprovalTasksForRole(roleType: string): string[] {
     const approvalMap: Record<string, string[]> = {
       'executor': ['delete', 'modify-critical', 'deploy'],
       'coordinator': ['restructure', 'terminate-agent'],
@@ -152,21 +189,57 @@ export class EnhancedCabal extends EventEmitter {
     // Assign agents to subtasks
     const assignments = await this.assignSubtasks(taskDescription, requiredRoles);
     
+    // Report the run as a workflow, one step per subtask, so observers can follow it
+    const workflowId = `workflow-${Date.now()}`;
+    const steps = assignments.map((assignment, i) => ({
+      id: `step-${i + 1}`,
+      name: assignment.subtask,
+      agent: assignment.agentId,
+      state: 'pending',
+      startedAt: 0,
+      endedAt: 0
+    }));
+    const workflow = {
+      id: workflowId,
+      name: taskDescription,
+      status: 'running',
+      agents: assignments.map(a => a.agentId),
+      steps
+    };
+    this.emit('workflow:update', workflow);
+
     // Execute in parallel with monitoring
     const results = await Promise.all(
-      assignments.map(async (assignment) => {
+      assignments.map(async (assignment, i) => {
         const agent = this.agents.get(assignment.agentId);
         if (!agent) return null;
 
-        return agent.executeTask(assignment.subtask, {
-          mainTask: taskDescription,
-          role: assignment.role
-        });
+        const step = steps[i];
+        step.state = 'running';
+        step.startedAt = Date.now();
+        this.emit('workflow:step', { workflowId, step: { ...step } });
+
+        try {
+          const result = await agent.executeTask(assignment.subtask, {
+            mainTask: taskDescription,
+            role: assignment.role
+          });
+          step.state = result && result.success ? 'done' : 'failed';
+          return result;
+        } catch (error) {
+          step.state = 'failed';
+          throw error;
+        } finally {
+          step.endedAt = Date.now();
+          this.emit('workflow:step', { workflowId, step: { ...step } });
+        }
       })
     );
 
     // Compile results
     const compilation = await this.compileResults(results, taskDescription);
+    workflow.status = compilation.success ? 'completed' : 'failed';
+    this.emit('workflow:update', workflow);
 
     // Final human review if needed
     if (humanOversight && compilation.confidence < 0.9) {
@@ -177,6 +250,143 @@ export class EnhancedCabal extends EventEmitter {
     return compilation;
   }
 
+  // Run a submitted workflow definition. Steps start once everything they depend on is done;
+  // a step whose dependency failed fails with it.
+  async runWorkflow(workflowId: string, definition: WorkflowDefinition): Promise<any> {
+    const steps = definition.steps.map(step => ({
+      id: step.id,
+      name: step.name || step.id,
+      agent: '',
+      state: 'pending',
+      dependsOn: step.dependsOn || [],
+      startedAt: 0,
+      endedAt: 0
+    }));
+    const workflow = {
+      id: workflowId,
+      name: definition.name,
+      status: 'running',
+      agents: [] as string[],
+      steps
+    };
+    this.emit('workflow:update', workflow);
+
+    const outputs: Record<string, Record<string, any>> = {};
+    const done = new Set<string>();
+    const failed = new Set<string>();
+    let remaining = definition.steps.map((_, i) => i);
+
+    while (remaining.length > 0) {
+      let blocked = remaining.filter(i => steps[i].dependsOn.some(dep => failed.has(dep)));
+      while (blocked.length > 0) {
+        for (const i of blocked) {
+          steps[i].state = 'failed';
+          failed.add(steps[i].id);
+          this.emit('workflow:step', { workflowId, step: { ...steps[i] } });
+        }
+        remaining = remaining.filter(i => !blocked.includes(i));
+        blocked = remaining.filter(i => steps[i].dependsOn.some(dep => failed.has(dep)));
+      }
+      if (remaining.length === 0) {
+        break;
+      }
+
+      const ready = remaining.filter(i => steps[i].dependsOn.every(dep => done.has(dep)));
+      if (ready.length === 0) {
+        // Unknown dependencies or a cycle: nothing left can ever start
+        for (const i of remaining) {
+          steps[i].state = 'failed';
+          this.emit('workflow:step', { workflowId, step: { ...steps[i] } });
+        }
+        break;
+      }
+
+      await Promise.all(ready.map(async (i) => {
+        const ok = await this.runWorkflowStep(workflowId, definition, definition.steps[i], steps[i], outputs);
+        (ok ? done : failed).add(steps[i].id);
+        if (steps[i].agent && !workflow.agents.includes(steps[i].agent)) {
+          workflow.agents.push(steps[i].agent);
+        }
+      }));
+      remaining = remaining.filter(i => !ready.includes(i));
+    }
+
+    workflow.status = done.size === steps.length ? 'completed' : 'failed';
+    this.emit('workflow:update', workflow);
+    return { workflowId, status: workflow.status, outputs };
+  }
+
+  private async runWorkflowStep(
+    workflowId: string,
+    definition: WorkflowDefinition,
+    stepDef: WorkflowStepDefinition,
+    step: any,
+    outputs: Record<string, Record<string, any>>
+  ): Promise<boolean> {
+    const update = () => this.emit('workflow:step', { workflowId, step: { ...step } });
+
+    const agent = this.findAgentForStep(stepDef);
+    if (!agent) {
+      step.state = 'failed';
+      update();
+      return false;
+    }
+    step.agent = agent.nodeId;
+
+    if (stepDef.requiresApproval) {
+      step.state = 'waiting-for-human';
+      update();
+      const approval = await this.coordinator['requestHumanApproval']({
+        agentId: agent.nodeId,
+        decisionType: 'workflow-step',
+        task: stepDef.approvalPrompt || step.name,
+        workflowId,
+        stepId: stepDef.id
+      });
+      if (!approval || !approval.approved) {
+        step.state = 'failed';
+        update();
+        return false;
+      }
+    }
+
+    // Inputs reference workflow inputs as ${inputs.<name>} and upstream results as
+    // ${steps.<id>.outputs.<name>}
+    const inputs: Record<string, string> = {};
+    for (const [name, value] of Object.entries(stepDef.inputs || {})) {
+      inputs[name] = value.replace(/\$\{(steps\.([^.}]+)\.outputs\.([^}]+)|inputs\.([^}]+))\}/g,
+        (_, __, stepId, output, input) => String(input
+          ? definition.inputs?.[input] ?? ''
+          : outputs[stepId]?.[output] ?? ''));
+    }
+
+    step.state = 'running';
+    step.startedAt = Date.now();
+    update();
+    try {
+      const result = await agent.executeTask(stepDef.task || step.name, {
+        workflowId,
+        stepId: stepDef.id,
+        inputs
+      });
+      outputs[stepDef.id] = result?.outputs || { result: result?.result ?? result };
+      step.state = result && result.success !== false ? 'done' : 'failed';
+    } catch (error) {
+      step.state = 'failed';
+    }
+    step.endedAt = Date.now();
+    update();
+    return step.state === 'done';
+  }
+
+  // A step names a role (the agent type) or a capability; without a registry only roles can be
+  // matched, so a capability step takes any free agent
+  protected findAgentForStep(step: WorkflowStepDefinition): AutonomousAgent | undefined {
+    const agents = Array.from(this.agents.values())
+      .filter(agent => !step.role || agent.nodeId.startsWith(`${step.role}-`));
+    return agents.find(agent => !agent.getStatus().currentTask) || agents[0];
+  }
+
   private async coordinateAgents(msg: any): Promise<any> {
     const { task, agents } = msg;
     
@@ -285,6 +495,16 @@ export class EnhancedCabal extends EventEmitter {
     });
   }
 
+  // Policies are set on the coordinator by node ID
+  setAgentPolicy(nodeId: string, policy: Partial<AgentPolicy>): AgentPolicy {
+    this.coordinator.setAgentPolicy(nodeId, { ...policy, agentId: nodeId });
+    return this.coordinator.getAgentPolicy(nodeId)!;
+  }
+
+  getAgentPolicies(): AgentPolicy[] {
+    return this.coordinator.getPolicies();
+  }
+
   // Human interface methods
   respondToRequest(requestId: string, response: any) {
     this.coordinator.respondToRequest(requestId, response);
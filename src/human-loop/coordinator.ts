@@ -80,6 +80,15 @@ export class HumanInTheLoopCoordinator extends EventEmitter {
 
   private async handleDecision(msg: any): Promise<any> {
     const policy = this.policies.get(msg.agentId);
+
+    // Report every decision so observers can preview policy changes against real traffic
+    this.emit('agent:decision', {
+      agentId: msg.agentId,
+      decisionType: msg.decisionType,
+      decision: msg.decision,
+      confidence: msg.confidence,
+      timestamp: Date.now()
+    });
     
     // Check if this decision type requires human approval
     if (policy && policy.requiresApprovalFor.includes(msg.decisionType)) {
@@ -173,6 +182,14 @@ export class HumanInTheLoopCoordinator extends EventEmitter {
     });
   }
 
+  getAgentPolicy(agentId: string): AgentPolicy | undefined {
+    return this.policies.get(agentId);
+  }
+
+  getPolicies(): AgentPolicy[] {
+    return Array.from(this.policies.values());
+  }
+
   // Human responds to a request
   respondToRequest(requestId: string, response: any) {
     if (this.pendingRequests.has(requestId)) {
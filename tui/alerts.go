@@ -0,0 +1,468 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Alert severities
+const (
+	severityInfo     = "info"
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+// AlertRule is a locally configured threshold over SystemStats or AgentInfo.
+// Expr has the form "<metric> <op> <value>"; metrics prefixed with "agent."
+// are evaluated for every agent, e.g. "agent.Status == offline".
+type AlertRule struct {
+	Name       string  `json:"name"`
+	Expr       string  `json:"expr"`
+	For        string  `json:"for"`
+	Severity   string  `json:"severity"`
+	Hysteresis float64 `json:"hysteresis"`
+
+	metric    string
+	op        string
+	threshold float64
+	text      string
+	numeric   bool
+	hold      time.Duration
+}
+
+var defaultAlertRules = []AlertRule{
+	{Name: "Low success rate", Expr: "SuccessRate < 0.8", For: "5m", Severity: severityCritical, Hysteresis: 0.05},
+	{Name: "Agent offline", Expr: "agent.Status == offline", For: "60s", Severity: severityWarning},
+	{Name: "Event storm", Expr: "EventsPerMinute > 500", For: "1m", Severity: severityWarning, Hysteresis: 50},
+}
+
+// LoadAlertRules reads a JSON array of rules; an empty path yields the defaults
+func LoadAlertRules(path string) ([]AlertRule, error) {
+	rules := defaultAlertRules
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		rules = nil
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	parsed := make([]AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.parse(); err != nil {
+			return nil, fmt.Errorf("alert rule %q: %w", rule.Name, err)
+		}
+		parsed = append(parsed, rule)
+	}
+	return parsed, nil
+}
+
+func (r *AlertRule) parse() error {
+	fields := strings.Fields(r.Expr)
+	if len(fields) != 3 {
+		return fmt.Errorf("expression %q must look like \"<metric> <op> <value>\"", r.Expr)
+	}
+	r.metric, r.op, r.text = fields[0], fields[1], fields[2]
+	switch r.op {
+	case "<", "<=", ">", ">=", "==", "!=":
+	default:
+		return fmt.Errorf("unknown operator %q", r.op)
+	}
+	if v, err := strconv.ParseFloat(r.text, 64); err == nil {
+		r.threshold, r.numeric = v, true
+	} else if r.op != "==" && r.op != "!=" {
+		return fmt.Errorf("%q needs a numeric threshold", r.op)
+	}
+	if r.For != "" {
+		hold, err := time.ParseDuration(r.For)
+		if err != nil {
+			return fmt.Errorf("invalid for-duration %q", r.For)
+		}
+		r.hold = hold
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = severityWarning
+	case severityInfo, severityWarning, severityCritical:
+	default:
+		return fmt.Errorf("unknown severity %q", r.Severity)
+	}
+	return nil
+}
+
+func (r AlertRule) perAgent() bool {
+	return strings.HasPrefix(r.metric, "agent.")
+}
+
+// matches reports whether the condition holds. While an alert is firing the
+// threshold is shifted by the hysteresis so a value hovering at the boundary
+// does not flap between firing and resolved.
+func (r AlertRule) matches(value interface{}, firing bool) bool {
+	if !r.numeric {
+		s := fmt.Sprint(value)
+		if r.op == "==" {
+			return s == r.text
+		}
+		return s != r.text
+	}
+
+	v, ok := toFloat(value)
+	if !ok {
+		return false
+	}
+	threshold := r.threshold
+	if firing {
+		switch r.op {
+		case "<", "<=":
+			threshold += r.Hysteresis
+		case ">", ">=":
+			threshold -= r.Hysteresis
+		}
+	}
+	switch r.op {
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	case ">":
+		return v > threshold
+	case ">=":
+		return v >= threshold
+	case "==":
+		return v == threshold
+	default:
+		return v != threshold
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// systemMetric reads a metric from the latest stats. Response time and success rate only mean
+// something once a task has completed; before that they read as zero and are left out.
+func systemMetric(stats SystemStats, name string) (interface{}, bool) {
+	switch name {
+	case "TotalAgents":
+		return stats.TotalAgents, true
+	case "OnlineAgents":
+		return stats.OnlineAgents, true
+	case "TasksCompleted":
+		return stats.TasksCompleted, true
+	case "AvgResponseTime":
+		return stats.AvgResponseTime, stats.TasksCompleted > 0
+	case "SuccessRate":
+		return stats.SuccessRate, stats.TasksCompleted > 0
+	case "EventsPerMinute":
+		return stats.EventsPerMinute, true
+	}
+	return nil, false
+}
+
+func agentMetric(agent AgentInfo, name string) (interface{}, bool) {
+	switch strings.TrimPrefix(name, "agent.") {
+	case "Status":
+		return agent.Status, true
+	case "Tasks":
+		return agent.Tasks, true
+	case "SuccessRate":
+		return agent.SuccessRate, agent.Tasks > 0
+	case "ResponseTime":
+		return agent.ResponseTime, agent.Tasks > 0
+	}
+	return nil, false
+}
+
+// Alert is the live state of one rule for one subject (the system or an agent)
+type Alert struct {
+	Rule         AlertRule
+	Subject      string
+	Value        string
+	PendingSince time.Time
+	FiredAt      time.Time
+	ResolvedAt   time.Time
+	Acknowledged bool
+	Source       string // set for alerts raised outside the rules, such as flow-balance patterns
+}
+
+func (a *Alert) firing() bool {
+	return !a.FiredAt.IsZero() && a.ResolvedAt.IsZero()
+}
+
+type alertEngine struct {
+	rules   []AlertRule
+	active  map[string]*Alert
+	history []*Alert
+}
+
+const maxAlertHistory = 100
+
+func newAlertEngine(rules []AlertRule) *alertEngine {
+	return &alertEngine{rules: rules, active: make(map[string]*Alert)}
+}
+
+// evaluate runs every rule against the latest data, returning alerts that started firing.
+// System rules are skipped until the first stats snapshot arrives, and rules on averages until
+// a task has completed.
+func (e *alertEngine) evaluate(now time.Time, stats *SystemStats, agents []AgentInfo) []*Alert {
+	var fired []*Alert
+	seen := map[string]bool{}
+	check := func(rule AlertRule, subject string, value interface{}) {
+		key := rule.Name + "\x00" + subject
+		seen[key] = true
+		alert := e.active[key]
+		if !rule.matches(value, alert != nil && alert.firing()) {
+			if alert != nil {
+				if alert.firing() {
+					alert.ResolvedAt = now
+					e.archive(alert)
+				}
+				delete(e.active, key)
+			}
+			return
+		}
+
+		if alert == nil {
+			alert = &Alert{Rule: rule, Subject: subject, PendingSince: now}
+			e.active[key] = alert
+		}
+		alert.Value = fmt.Sprint(value)
+		if !alert.firing() && now.Sub(alert.PendingSince) >= rule.hold {
+			alert.FiredAt = now
+			fired = append(fired, alert)
+		}
+	}
+
+	for _, rule := range e.rules {
+		if rule.perAgent() {
+			for _, agent := range agents {
+				if value, ok := agentMetric(agent, rule.metric); ok {
+					check(rule, agent.Name, value)
+				}
+			}
+			continue
+		}
+		if stats == nil {
+			continue
+		}
+		if value, ok := systemMetric(*stats, rule.metric); ok {
+			check(rule, "system", value)
+		}
+	}
+
+	// Subjects that disappeared (e.g. an agent that left) resolve their alerts
+	for key, alert := range e.active {
+		if !seen[key] && alert.Source == "" {
+			if alert.firing() {
+				alert.ResolvedAt = now
+				e.archive(alert)
+			}
+			delete(e.active, key)
+		}
+	}
+	return fired
+}
+
+// sync replaces the alerts from an outside source with its current findings. The source applies
+// its own thresholds, so new alerts fire at once; ones no longer reported resolve.
+func (e *alertEngine) sync(source string, now time.Time, current []*Alert) []*Alert {
+	var fired []*Alert
+	seen := map[string]bool{}
+	for _, alert := range current {
+		key := source + "\x00" + alert.Rule.Name + "\x00" + alert.Subject
+		seen[key] = true
+		if existing := e.active[key]; existing != nil {
+			existing.Value = alert.Value
+			continue
+		}
+		alert.Source, alert.PendingSince, alert.FiredAt = source, now, now
+		e.active[key] = alert
+		fired = append(fired, alert)
+	}
+	for key, alert := range e.active {
+		if alert.Source == source && !seen[key] {
+			alert.ResolvedAt = now
+			e.archive(alert)
+			delete(e.active, key)
+		}
+	}
+	return fired
+}
+
+func (e *alertEngine) archive(alert *Alert) {
+	e.history = append([]*Alert{alert}, e.history...)
+	if len(e.history) > maxAlertHistory {
+		e.history = e.history[:maxAlertHistory]
+	}
+}
+
+// firing returns firing alerts, most severe and most recent first
+func (e *alertEngine) firing() []*Alert {
+	var alerts []*Alert
+	for _, alert := range e.active {
+		if alert.firing() {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if si, sj := severityRank(alerts[i].Rule.Severity), severityRank(alerts[j].Rule.Severity); si != sj {
+			return si > sj
+		}
+		return alerts[i].FiredAt.After(alerts[j].FiredAt)
+	})
+	return alerts
+}
+
+// worstUnacknowledged is the highest severity among firing alerts nobody has acknowledged
+func (e *alertEngine) worstUnacknowledged() string {
+	worst := ""
+	for _, alert := range e.active {
+		if alert.firing() && !alert.Acknowledged && severityRank(alert.Rule.Severity) > severityRank(worst) {
+			worst = alert.Rule.Severity
+		}
+	}
+	return worst
+}
+
+func (e *alertEngine) acknowledgeAll() {
+	for _, alert := range e.active {
+		alert.Acknowledged = true
+	}
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case severityCritical:
+		return 3
+	case severityWarning:
+		return 2
+	case severityInfo:
+		return 1
+	}
+	return 0
+}
+
+func severityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case severityCritical:
+		return offlineStyle
+	case severityWarning:
+		return busyStyle
+	default:
+		return statusStyle
+	}
+}
+
+// frameStyle returns the content border for the current alert state, reusing the agent notification borders
+func (m *controlCenterModel) frameStyle() lipgloss.Style {
+	switch m.alerts.worstUnacknowledged() {
+	case severityCritical:
+		return contentStyle.BorderForeground(criticalBorderStyle.GetBorderTopForeground())
+	case severityWarning:
+		return contentStyle.BorderForeground(notificationBorderStyle.GetBorderTopForeground())
+	}
+	return contentStyle
+}
+
+func (m *controlCenterModel) evaluateAlerts(now time.Time) {
+	var stats *SystemStats
+	if len(m.liveHistory().system) > 0 {
+		stats = &m.stats
+	}
+	m.alerts.evaluate(now, stats, m.liveAgents(now))
+	m.alerts.sync(flowAlertSource, now, m.flow.alerts(now))
+	m.alertCursor = min(m.alertCursor, max(0, len(m.alerts.firing())-1))
+}
+
+func (m *controlCenterModel) renderAlertsTab() string {
+	title := titleStyle.Render("🚨 Alerts")
+
+	var content strings.Builder
+	firing := m.alerts.firing()
+	if len(firing) == 0 {
+		content.WriteString(onlineStyle.Render("No alerts firing") + "\n")
+	}
+	for i, alert := range firing {
+		cursor := "  "
+		if i == m.alertCursor {
+			cursor = "▸ "
+		}
+		ack := "  "
+		if alert.Acknowledged {
+			ack = onlineStyle.Render("✓ ")
+		}
+		content.WriteString(fmt.Sprintf("%s%s%s %-24s %-16s %s %s\n",
+			cursor,
+			ack,
+			severityStyle(alert.Rule.Severity).Render(fmt.Sprintf("%-8s", alert.Rule.Severity)),
+			truncate(alert.Rule.Name, 24),
+			truncate(alert.Subject, 16),
+			alert.FiredAt.Format("15:04:05"),
+			statusStyle.Render(fmt.Sprintf("%s (now %s)", alert.Rule.Expr, alert.Value)),
+		))
+	}
+
+	if len(m.alerts.history) > 0 {
+		content.WriteString("\n" + statLabelStyle.Render("Resolved") + "\n")
+		for _, alert := range m.alerts.history[:min(10, len(m.alerts.history))] {
+			content.WriteString(statusStyle.Render(fmt.Sprintf("    %-8s %-24s %-16s %s → %s\n",
+				alert.Rule.Severity,
+				truncate(alert.Rule.Name, 24),
+				truncate(alert.Subject, 16),
+				alert.FiredAt.Format("15:04:05"),
+				alert.ResolvedAt.Format("15:04:05"),
+			)))
+		}
+	}
+
+	help := statusStyle.Render("↑/↓: select • a: acknowledge (✓) • A: acknowledge all")
+	return m.frameStyle().
+		Width(m.width - 4).
+		Height(m.height - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", content.String(), help))
+}
+
+func (m *controlCenterModel) handleAlertKey(key string) {
+	firing := m.alerts.firing()
+	switch key {
+	case "up", "k":
+		m.alertCursor = max(0, m.alertCursor-1)
+	case "down", "j":
+		m.alertCursor = min(max(0, len(firing)-1), m.alertCursor+1)
+	case "a", "enter":
+		if m.alertCursor < len(firing) {
+			firing[m.alertCursor].Acknowledged = true
+		}
+	case "A":
+		m.alerts.acknowledgeAll()
+	}
+}
+
+func mustDefaultAlertRules() []AlertRule {
+	rules, err := LoadAlertRules("")
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuccessRateAlertWaitsForTasks(t *testing.T) {
+	rules, err := LoadAlertRules("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := newAlertEngine(rules)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// A connected but idle swarm reports no success rate at all
+	idle := SystemStats{TotalAgents: 2, OnlineAgents: 2}
+	for i := 0; i <= 10; i++ {
+		if fired := engine.evaluate(start.Add(time.Duration(i)*time.Minute), &idle, nil); len(fired) > 0 {
+			t.Fatalf("idle swarm fired %q", fired[0].Rule.Name)
+		}
+	}
+
+	failing := SystemStats{TotalAgents: 2, OnlineAgents: 2, TasksCompleted: 4, SuccessRate: 0.5}
+	now := start.Add(11 * time.Minute)
+	engine.evaluate(now, &failing, nil)
+	fired := engine.evaluate(now.Add(5*time.Minute), &failing, nil)
+	if len(fired) != 1 || fired[0].Rule.Name != "Low success rate" {
+		t.Fatalf("fired %v, want the low success rate alert", fired)
+	}
+
+	// Within the hysteresis band the alert holds; past it, it resolves
+	holding := failing
+	holding.SuccessRate = 0.82
+	engine.evaluate(now.Add(6*time.Minute), &holding, nil)
+	if len(engine.firing()) != 1 {
+		t.Fatal("alert resolved inside the hysteresis band")
+	}
+	recovered := failing
+	recovered.SuccessRate = 0.9
+	engine.evaluate(now.Add(7*time.Minute), &recovered, nil)
+	if len(engine.firing()) != 0 {
+		t.Error("alert still firing after recovery")
+	}
+}
+
+func TestAgentAveragesWaitForTasks(t *testing.T) {
+	rule := AlertRule{Name: "Fast agent", Expr: "agent.ResponseTime < 100", For: "0s", Severity: severityWarning}
+	if err := rule.parse(); err != nil {
+		t.Fatal(err)
+	}
+	engine := newAlertEngine([]AlertRule{rule})
+	now := time.Now()
+	if fired := engine.evaluate(now, nil, []AgentInfo{{Name: "coder"}}); len(fired) != 0 {
+		t.Errorf("agent without tasks fired %v", fired)
+	}
+	if fired := engine.evaluate(now, nil, []AgentInfo{{Name: "coder", Tasks: 1, ResponseTime: 50}}); len(fired) != 1 {
+		t.Errorf("fired %v, want one alert", fired)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// auditEntry is one human decision. Hash covers every other field including PrevHash,
+// so editing or removing an entry breaks the chain from that point on.
+type auditEntry struct {
+	Seq         int    `json:"seq"`
+	Time        int64  `json:"time"` // epoch milliseconds
+	RequestID   string `json:"requestId"`
+	Agent       string `json:"agent"`
+	RequestType string `json:"requestType"`
+	Priority    string `json:"priority"`
+	Context     string `json:"context"`
+	Choice      string `json:"choice"`
+	AnsweredBy  string `json:"answeredBy"`
+	WaitedMs    int64  `json:"waitedMs"`
+	PrevHash    string `json:"prevHash"`
+	Hash        string `json:"hash"`
+}
+
+func (e auditEntry) computeHash() string {
+	e.Hash = ""
+	data, _ := json.Marshal(e)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (e auditEntry) at() time.Time {
+	return time.UnixMilli(e.Time)
+}
+
+func (e auditEntry) waited() time.Duration {
+	return time.Duration(e.WaitedMs) * time.Millisecond
+}
+
+// auditLog is the append-only, hash-chained record of every human:response sent
+type auditLog struct {
+	entries []auditEntry
+	file    *os.File
+	broken  int // sequence number of the first entry failing verification, 0 if intact
+}
+
+// openAuditLog loads and verifies an existing log and appends to it. An empty path keeps the log in memory.
+// A log that cannot be read to the end is an error: appending after a partial load would extend
+// the chain from the wrong entry.
+func openAuditLog(path string) (*auditLog, error) {
+	log := &auditLog{}
+	if path == "" {
+		return log, nil
+	}
+
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var entry auditEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				entry = auditEntry{Seq: len(log.entries) + 1}
+			}
+			log.entries = append(log.entries, entry)
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading audit log %s after entry %d: %w", path, len(log.entries), err)
+		}
+		log.broken = verifyAuditChain(log.entries)
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if log.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// verifyAuditChain returns the sequence number of the first entry whose hash or link is wrong
+func verifyAuditChain(entries []auditEntry) int {
+	prev := ""
+	for i, entry := range entries {
+		if entry.Seq != i+1 || entry.PrevHash != prev || entry.computeHash() != entry.Hash {
+			return i + 1
+		}
+		prev = entry.Hash
+	}
+	return 0
+}
+
+func (l *auditLog) append(entry auditEntry) error {
+	entry.Seq = len(l.entries) + 1
+	if len(l.entries) > 0 {
+		entry.PrevHash = l.entries[len(l.entries)-1].Hash
+	}
+	entry.Hash = entry.computeHash()
+	l.entries = append(l.entries, entry)
+
+	if l.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func (l *auditLog) Close() {
+	if l.file != nil {
+		l.file.Close()
+	}
+}
+
+func (e auditEntry) matches(filter string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	for _, field := range []string{e.RequestID, e.Agent, e.RequestType, e.Priority, e.Context, e.Choice, e.AnsweredBy} {
+		if strings.Contains(strings.ToLower(field), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// filtered returns matching entries, newest first
+func (l *auditLog) filtered(filter string) []auditEntry {
+	var out []auditEntry
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if l.entries[i].matches(filter) {
+			out = append(out, l.entries[i])
+		}
+	}
+	return out
+}
+
+// exportAuditCSV writes entries oldest first, hashes included so the chain can be re-checked
+func exportAuditCSV(path string, entries []auditEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"seq", "time", "request_id", "agent", "request_type", "priority", "context", "choice", "answered_by", "waited_ms", "prev_hash", "hash"})
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		w.Write([]string{
+			strconv.Itoa(e.Seq),
+			e.at().UTC().Format(time.RFC3339Nano),
+			e.RequestID,
+			e.Agent,
+			e.RequestType,
+			e.Priority,
+			e.Context,
+			e.Choice,
+			e.AnsweredBy,
+			strconv.FormatInt(e.WaitedMs, 10),
+			e.PrevHash,
+			e.Hash,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// recordAudit appends a sent response to the audit log
+func (m *controlCenterModel) recordAudit(request *HumanRequest, choice string, by string, now time.Time) {
+	if by == "operator" {
+		by = "operator:" + m.operator
+	}
+	err := m.audit.append(auditEntry{
+		Time:        now.UnixMilli(),
+		RequestID:   request.ID,
+		Agent:       m.agentLabel(request.From),
+		RequestType: request.Type,
+		Priority:    request.Priority,
+		Context:     request.contextSummary(),
+		Choice:      choice,
+		AnsweredBy:  by,
+		WaitedMs:    m.sla.waited(request, now).Milliseconds(),
+	})
+	if err != nil {
+		m.requestError = fmt.Sprintf("audit log: %v", err)
+	}
+}
+
+func (m *controlCenterModel) renderAuditLog(width int) string {
+	var content strings.Builder
+	if m.audit.broken > 0 {
+		content.WriteString(offlineStyle.Render(fmt.Sprintf("✗ Hash chain broken at entry %d: the log was modified outside the control center", m.audit.broken)) + "\n")
+	} else {
+		content.WriteString(onlineStyle.Render(fmt.Sprintf("✓ Hash chain intact (%d entries)", len(m.audit.entries))) + "\n")
+	}
+	if m.promptingAudit {
+		content.WriteString(m.auditFilter.View() + "\n")
+	} else if filter := m.auditFilter.Value(); filter != "" {
+		content.WriteString(statusStyle.Render(fmt.Sprintf("Filter: %q", filter)) + "\n")
+	}
+	if m.requestError != "" {
+		content.WriteString(offlineStyle.Render(m.requestError) + "\n")
+	}
+	if m.auditNotice != "" {
+		content.WriteString(statusStyle.Render(m.auditNotice) + "\n")
+	}
+	content.WriteString("\n")
+
+	header := fmt.Sprintf("%-4s %-19s %-14s %-9s %-7s %-22s %-20s %8s  %-8s  %s", "#", "Time", "Agent", "Type", "Prio", "Choice", "Answered by", "Waited", "Hash", "Context")
+	content.WriteString(statLabelStyle.Render(truncate(header, width)) + "\n")
+	entries := m.audit.filtered(m.auditFilter.Value())
+	if len(entries) == 0 {
+		content.WriteString(statusStyle.Render("No human decisions recorded") + "\n")
+	}
+	for _, e := range entries[:min(len(entries), max(1, m.height-18))] {
+		line := fmt.Sprintf("%-4d %-19s %-14s %-9s %-7s %-22s %-20s %8s  %-8s  %s",
+			e.Seq,
+			e.at().Format("2006-01-02 15:04:05"),
+			truncate(e.Agent, 14),
+			e.RequestType,
+			e.Priority,
+			truncate(e.Choice, 22),
+			truncate(e.AnsweredBy, 20),
+			formatDuration(e.waited()),
+			e.Hash[:min(8, len(e.Hash))],
+			e.Context,
+		)
+		if m.audit.broken > 0 && e.Seq >= m.audit.broken {
+			line = offlineStyle.Render(truncate(line, width))
+		} else {
+			line = truncate(line, width)
+		}
+		content.WriteString(line + "\n")
+	}
+	return content.String()
+}
+
+func (m *controlCenterModel) handleAuditKey(key string) {
+	m.auditNotice = ""
+	switch key {
+	case "/":
+		m.promptingAudit = true
+		m.auditFilter.Focus()
+	case "e":
+		m.exportAudit()
+	case "esc", "L":
+		m.showAudit = false
+	}
+}
+
+func (m *controlCenterModel) exportAudit() {
+	path := fmt.Sprintf("cabal-audit-%s.csv", time.Now().Format("20060102-150405"))
+	if err := exportAuditCSV(path, m.audit.filtered(m.auditFilter.Value())); err != nil {
+		m.requestError = fmt.Sprintf("export failed: %v", err)
+		return
+	}
+	m.auditNotice = fmt.Sprintf("Exported %d entries to %s", len(m.audit.filtered(m.auditFilter.Value())), path)
+}
+
+func currentOperator() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+func defaultAuditLogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "cabal-audit.jsonl"
+	}
+	return filepath.Join(home, ".cabal", "audit.jsonl")
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeAuditLog records three decisions in a fresh log file and returns its path
+func writeAuditLog(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, choice := range []string{"approve", "deny", "approve"} {
+		if err := log.append(auditEntry{Time: int64(1700000000000 + i), RequestID: "req", Agent: "coder", Choice: choice, AnsweredBy: "operator:ann"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log.Close()
+	return path
+}
+
+func rewriteAuditLog(t *testing.T, path string, edit func(lines []string) []string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := edit(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"))
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditChainSurvivesReopen(t *testing.T) {
+	path := writeAuditLog(t)
+	log, err := openAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	if len(log.entries) != 3 || log.broken != 0 {
+		t.Fatalf("%d entries, broken at %d; want 3 intact", len(log.entries), log.broken)
+	}
+	if err := log.append(auditEntry{RequestID: "req-4", Choice: "approve"}); err != nil {
+		t.Fatal(err)
+	}
+	if broken := verifyAuditChain(log.entries); broken != 0 {
+		t.Errorf("chain broken at %d after appending to a reopened log", broken)
+	}
+}
+
+func TestAuditTamperDetection(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		edit func(lines []string) []string
+		want int
+	}{
+		{"edited choice", func(lines []string) []string {
+			lines[1] = strings.Replace(lines[1], `"deny"`, `"approve"`, 1)
+			return lines
+		}, 2},
+		{"removed entry", func(lines []string) []string {
+			return append(lines[:1], lines[2:]...)
+		}, 2},
+		{"reordered entries", func(lines []string) []string {
+			lines[0], lines[1] = lines[1], lines[0]
+			return lines
+		}, 1},
+		{"garbled line", func(lines []string) []string {
+			lines[2] = "not json"
+			return lines
+		}, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeAuditLog(t)
+			rewriteAuditLog(t, path, tc.edit)
+			log, err := openAuditLog(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer log.Close()
+			if log.broken != tc.want {
+				t.Errorf("broken at %d, want %d", log.broken, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuditLogUnreadable(t *testing.T) {
+	path := writeAuditLog(t)
+	rewriteAuditLog(t, path, func(lines []string) []string {
+		return append(lines, strings.Repeat("x", 2*1024*1024))
+	})
+	if _, err := openAuditLog(path); err == nil {
+		t.Error("opened a log with an unreadable line, want an error")
+	}
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Auto-responses wait this long before they are sent so they can be undone
+const autoResponseGrace = 5 * time.Second
+
+// Auto-decisions kept in the log
+const maxAutoDecisions = 100
+
+// AutoRule answers matching human requests without operator input. Agent, Type and Priority
+// match the request fields; Match maps JSONPath-like selectors such as "$.context.task" to
+// glob patterns. Response is "approve", "reject" or a JSON object sent as-is.
+type AutoRule struct {
+	Name     string            `json:"name"`
+	Agent    string            `json:"agent,omitempty"`
+	Type     string            `json:"type,omitempty"`
+	Priority string            `json:"priority,omitempty"`
+	Match    map[string]string `json:"match,omitempty"`
+	Response json.RawMessage   `json:"response"`
+	Disabled bool              `json:"disabled,omitempty"`
+}
+
+// LoadAutoRules reads a JSON array of rules; a missing file yields no rules
+func LoadAutoRules(file string) ([]*AutoRule, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rules []*AutoRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("auto rule %q: %w", rule.Name, err)
+		}
+	}
+	return rules, nil
+}
+
+func saveAutoRules(file string, rules []*AutoRule) error {
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
+
+func (r *AutoRule) validate() error {
+	for selector, pattern := range r.Match {
+		if _, err := parseSelector(selector); err != nil {
+			return err
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("bad pattern %q for %s", pattern, selector)
+		}
+	}
+	if _, err := r.response(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// response decodes the configured reply into the payload sent as human:response
+func (r *AutoRule) response() (interface{}, error) {
+	if len(r.Response) == 0 {
+		return nil, fmt.Errorf("missing response")
+	}
+	var value interface{}
+	if err := json.Unmarshal(r.Response, &value); err != nil {
+		return nil, fmt.Errorf("bad response: %w", err)
+	}
+	switch value {
+	case "approve":
+		return map[string]interface{}{"approved": true, "auto": true}, nil
+	case "reject":
+		return map[string]interface{}{"approved": false, "auto": true, "reason": "auto-rejected"}, nil
+	}
+	if _, ok := value.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("response must be \"approve\", \"reject\" or an object")
+	}
+	return value, nil
+}
+
+func (r *AutoRule) matches(request *HumanRequest, agentName string) bool {
+	if r.Disabled {
+		return false
+	}
+	if r.Agent != "" && !globMatch(r.Agent, request.From) && !globMatch(r.Agent, agentName) {
+		return false
+	}
+	if r.Type != "" && !globMatch(r.Type, request.Type) {
+		return false
+	}
+	if r.Priority != "" && !globMatch(r.Priority, request.Priority) {
+		return false
+	}
+	for selector, pattern := range r.Match {
+		steps, err := parseSelector(selector)
+		if err != nil {
+			return false
+		}
+		value, ok := resolveSelector(request.raw, steps)
+		if !ok || !globMatch(pattern, selectorText(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe renders the rule in the compact syntax accepted by the rule prompt
+func (r *AutoRule) describe() string {
+	var parts []string
+	for _, field := range [][2]string{{"agent", r.Agent}, {"type", r.Type}, {"priority", r.Priority}} {
+		if field[1] != "" {
+			parts = append(parts, field[0]+"="+field[1])
+		}
+	}
+	for _, selector := range sortedKeys(r.Match) {
+		parts = append(parts, selector+"="+r.Match[selector])
+	}
+	var response interface{}
+	json.Unmarshal(r.Response, &response)
+	if s, ok := response.(string); ok {
+		parts = append(parts, "-> "+s)
+	} else {
+		parts = append(parts, "-> "+string(r.Response))
+	}
+	return strings.Join(parts, " ")
+}
+
+func globMatch(pattern, value string) bool {
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+// selectorText formats a selected value for glob matching; scalars match by their JSON text
+func selectorText(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+var (
+	selectorStep  = regexp.MustCompile(`^([A-Za-z_][\w-]*)?((?:\[\d+\])*)$`)
+	selectorIndex = regexp.MustCompile(`\d+`)
+)
+
+// parseSelector splits "$.context.files[0].name" into object keys and array indexes
+func parseSelector(selector string) ([]interface{}, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(selector, "$"), ".")
+	if rest == "" {
+		return nil, fmt.Errorf("empty selector %q", selector)
+	}
+	var steps []interface{}
+	for _, part := range strings.Split(rest, ".") {
+		match := selectorStep.FindStringSubmatch(part)
+		if match == nil || (match[1] == "" && match[2] == "") {
+			return nil, fmt.Errorf("bad selector %q", selector)
+		}
+		if match[1] != "" {
+			steps = append(steps, match[1])
+		}
+		for _, index := range selectorIndex.FindAllString(match[2], -1) {
+			n, _ := strconv.Atoi(index)
+			steps = append(steps, n)
+		}
+	}
+	return steps, nil
+}
+
+func resolveSelector(value interface{}, steps []interface{}) (interface{}, bool) {
+	for _, step := range steps {
+		switch key := step.(type) {
+		case string:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object[key]; !ok {
+				return nil, false
+			}
+		case int:
+			array, ok := value.([]interface{})
+			if !ok || key >= len(array) {
+				return nil, false
+			}
+			value = array[key]
+		}
+	}
+	return value, true
+}
+
+// parseRuleText parses the compact rule syntax:
+// "[name:] agent=alpha type=approval $.context.task=run-* -> approve"
+func parseRuleText(text string) (*AutoRule, error) {
+	conditions, action, ok := strings.Cut(text, "->")
+	if !ok {
+		return nil, fmt.Errorf("missing \"-> approve|reject|{json}\"")
+	}
+	rule := &AutoRule{}
+	if name, rest, ok := strings.Cut(conditions, ":"); ok && !strings.ContainsAny(name, "=$") {
+		rule.Name, conditions = strings.TrimSpace(name), rest
+	}
+	for _, field := range strings.Fields(conditions) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("condition %q must look like key=value", field)
+		}
+		switch {
+		case key == "agent":
+			rule.Agent = value
+		case key == "type":
+			rule.Type = value
+		case key == "priority":
+			rule.Priority = value
+		case strings.HasPrefix(key, "$"):
+			if rule.Match == nil {
+				rule.Match = map[string]string{}
+			}
+			rule.Match[key] = value
+		default:
+			return nil, fmt.Errorf("unknown condition %q", key)
+		}
+	}
+
+	action = strings.TrimSpace(action)
+	if action == "approve" || action == "reject" {
+		action = strconv.Quote(action)
+	}
+	rule.Response = json.RawMessage(action)
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// similarRule drafts a rule matching requests like this one
+func similarRule(request *HumanRequest, agentName string) string {
+	name := agentName + " " + request.Type
+	parts := []string{"agent=" + request.From, "type=" + request.Type}
+	if request.Priority != "" {
+		parts = append(parts, "priority="+request.Priority)
+	}
+	if task, ok := resolveSelector(request.raw, []interface{}{"context", "task"}); ok {
+		if s, ok := task.(string); ok && !strings.ContainsAny(s, " =") {
+			name += " " + s
+			parts = append(parts, "$.context.task="+s)
+		}
+	}
+	return name + ": " + strings.Join(parts, " ") + " -> approve"
+}
+
+// autoDecision records one automatic response
+type autoDecision struct {
+	At       time.Time
+	Request  *HumanRequest
+	Rule     *AutoRule
+	Response interface{}
+	State    string // scheduled, sent, undone or failed
+	Err      string
+}
+
+func (m *controlCenterModel) logAutoDecision(decision *autoDecision) {
+	m.autoDecisions = append([]*autoDecision{decision}, m.autoDecisions...)
+	if len(m.autoDecisions) > maxAutoDecisions {
+		m.autoDecisions = m.autoDecisions[:maxAutoDecisions]
+	}
+}
+
+// scheduleAutoResponse starts the grace period for the first rule matching the request
+func (m *controlCenterModel) scheduleAutoResponse(request *HumanRequest) {
+	for _, rule := range m.autoRules {
+		if !rule.matches(request, m.agentLabel(request.From)) {
+			continue
+		}
+		response, err := rule.response()
+		if err != nil {
+			continue
+		}
+		request.autoRule, request.autoAt = rule.Name, time.Now().Add(autoResponseGrace)
+		m.logAutoDecision(&autoDecision{
+			At:       time.Now(),
+			Request:  request,
+			Rule:     rule,
+			Response: response,
+			State:    "scheduled",
+		})
+		return
+	}
+}
+
+// sendDueAutoResponses sends scheduled responses whose grace period has passed
+func (m *controlCenterModel) sendDueAutoResponses(now time.Time) {
+	for i := len(m.autoDecisions) - 1; i >= 0; i-- {
+		decision := m.autoDecisions[i]
+		if decision.State != "scheduled" || now.Before(decision.Request.autoAt) {
+			continue
+		}
+		decision.Request.autoRule = ""
+		if _, pending := m.findHumanRequest(decision.Request.ID); pending == nil {
+			// Answered by hand or expired during the grace period
+			decision.State = "superseded"
+			continue
+		}
+		if err := m.respond(decision.Request, decision.Response, "auto:"+decision.Rule.Name); err != nil {
+			decision.State, decision.Err = "failed", err.Error()
+			continue
+		}
+		decision.State, decision.At = "sent", now
+	}
+}
+
+// latestScheduled finds the scheduled decision for a request, or the most recent one
+// when no request is selected
+func (m *controlCenterModel) latestScheduled(request *HumanRequest) *autoDecision {
+	for _, decision := range m.autoDecisions {
+		if decision.State == "scheduled" && (request == nil || decision.Request == request) {
+			return decision
+		}
+	}
+	return nil
+}
+
+// undoAutoResponse cancels a scheduled auto-response and leaves the request for the operator
+func (m *controlCenterModel) undoAutoResponse(request *HumanRequest) {
+	decision := m.latestScheduled(request)
+	if decision == nil {
+		m.requestError = "no auto-response waiting to be sent; sent responses cannot be recalled"
+		return
+	}
+	decision.State = "undone"
+	decision.Request.autoRule = ""
+}
+
+// stopAutoRule disables the rule behind the selected request's or latest auto-decision
+func (m *controlCenterModel) stopAutoRule(request *HumanRequest) {
+	var rule *AutoRule
+	if decision := m.latestScheduled(request); decision != nil {
+		rule = decision.Rule
+		m.undoAutoResponse(decision.Request)
+	} else {
+		for _, decision := range m.autoDecisions {
+			if request == nil || decision.Request == request {
+				rule = decision.Rule
+				break
+			}
+		}
+	}
+	if rule == nil {
+		m.requestError = "no auto-approval rule has fired yet"
+		return
+	}
+	rule.Disabled = true
+	if err := saveAutoRules(m.autoRulesFile, m.autoRules); err != nil {
+		m.requestError = fmt.Sprintf("saving rules: %v", err)
+	}
+}
+
+func (m *controlCenterModel) openRulePrompt(draft string) {
+	m.rulePrompt.SetValue(draft)
+	m.rulePrompt.CursorEnd()
+	m.rulePrompt.Focus()
+	m.promptingRule = true
+}
+
+func (m *controlCenterModel) addAutoRule(text string) {
+	rule, err := parseRuleText(text)
+	if err != nil {
+		m.requestError = err.Error()
+		return
+	}
+	if rule.Name == "" {
+		rule.Name = fmt.Sprintf("rule %d", len(m.autoRules)+1)
+	}
+	m.autoRules = append(m.autoRules, rule)
+	if err := saveAutoRules(m.autoRulesFile, m.autoRules); err != nil {
+		m.requestError = fmt.Sprintf("saving rules: %v", err)
+	}
+	// Apply the new rule to requests already waiting
+	for _, request := range m.humanRequests {
+		if request.autoRule == "" {
+			m.scheduleAutoResponse(request)
+		}
+	}
+}
+
+func (m *controlCenterModel) renderAutoRules(width int) string {
+	var content strings.Builder
+	if m.promptingRule {
+		content.WriteString(m.rulePrompt.View() + "\n")
+		content.WriteString(statusStyle.Render("  [name:] agent=… type=… priority=… $.context.field=glob -> approve|reject|{json}") + "\n\n")
+	}
+	if m.requestError != "" {
+		content.WriteString(offlineStyle.Render(m.requestError) + "\n\n")
+	}
+
+	content.WriteString(statLabelStyle.Render(fmt.Sprintf("Auto-approval rules (%d)", len(m.autoRules))) + "\n")
+	if len(m.autoRules) == 0 {
+		content.WriteString(statusStyle.Render("  No rules; press r on a routine request to add one") + "\n")
+	}
+	rules := append([]*AutoRule(nil), m.autoRules...)
+	sort.SliceStable(rules, func(i, j int) bool { return !rules[i].Disabled && rules[j].Disabled })
+	for _, rule := range rules {
+		mark, style := onlineStyle.Render("✓"), statValueStyle
+		if rule.Disabled {
+			mark, style = offlineStyle.Render("✗"), statusStyle
+		}
+		line := fmt.Sprintf("%s  %s", truncate(rule.Name, 24), rule.describe())
+		content.WriteString("  " + mark + " " + style.Render(truncate(line, width-4)) + "\n")
+	}
+
+	if len(m.autoDecisions) > 0 {
+		content.WriteString("\n" + statLabelStyle.Render("Auto-decisions") + "\n")
+		for _, decision := range m.autoDecisions[:min(8, len(m.autoDecisions))] {
+			data, _ := json.Marshal(decision.Response)
+			line := fmt.Sprintf("  %s %-10s %-14s %-9s %s via %q",
+				decision.At.Format("15:04:05"),
+				decision.State,
+				truncate(m.agentLabel(decision.Request.From), 14),
+				decision.Request.Type,
+				data,
+				decision.Rule.Name,
+			)
+			if decision.Err != "" {
+				line += ": " + decision.Err
+			}
+			content.WriteString(stateStyle(autoStateStyle(decision.State)).Render(truncate(line, width)) + "\n")
+		}
+	}
+	return content.String()
+}
+
+// autoStateStyle maps auto-decision states onto the shared state colours
+func autoStateStyle(state string) string {
+	switch state {
+	case "sent":
+		return stepDone
+	case "scheduled":
+		return stepRunning
+	case "failed":
+		return stepFailed
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxBackgroundActivity bounds each agent's background stream
+const maxBackgroundActivity = 200
+
+// backgroundActivity is one agent-to-agent message the human is not a party to
+type backgroundActivity struct {
+	at      time.Time
+	from    string
+	to      string
+	kind    string
+	content string
+
+	correlationID string
+	causationID   string
+}
+
+// backgroundMsg is forwarded from the bridge's agent:background event
+type backgroundMsg struct {
+	activity backgroundActivity
+}
+
+// backgroundContent renders a payload's content on one line
+func backgroundContent(content interface{}) string {
+	if content == nil {
+		return ""
+	}
+	if s, ok := content.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Sprint(content)
+	}
+	return string(data)
+}
+
+// addBackground files an activity under both agents involved
+func (m *model) addBackground(activity backgroundActivity) {
+	ids := []string{activity.from}
+	if activity.to != "" && activity.to != activity.from {
+		ids = append(ids, activity.to)
+	}
+	for _, id := range ids {
+		stream := append(m.background[id], activity)
+		if len(stream) > maxBackgroundActivity {
+			stream = stream[len(stream)-maxBackgroundActivity:]
+		}
+		m.background[id] = stream
+		if !m.showBackground || m.activeAgent >= len(m.agents) || m.agents[m.activeAgent].id != id {
+			m.backgroundUnseen[id]++
+		}
+	}
+}
+
+// renderBackgroundPanel lists the newest peer messages that fit, oldest at the top
+func (m *model) renderBackgroundPanel(agentID string, width, height int) string {
+	stream := m.background[agentID]
+
+	var content strings.Builder
+	content.WriteString(agentStyle.Render("💬 Background") + " " + statusStyle.Render(fmt.Sprintf("(%d)", len(stream))) + "\n\n")
+	if len(stream) == 0 {
+		content.WriteString(statusStyle.Render("No agent-to-agent activity"))
+		return content.String()
+	}
+
+	// Each entry takes three lines
+	rows := max(1, (height-3)/3)
+	for _, a := range stream[max(0, len(stream)-rows):] {
+		to := "*"
+		if a.to != "" {
+			to = m.agentDisplayName(a.to)
+		}
+		content.WriteString(statusStyle.Render(fmt.Sprintf("%s %s", a.at.Format("15:04:05"), truncate(a.kind, width-9))) + "\n")
+		content.WriteString(truncate(fmt.Sprintf("%s → %s", m.agentDisplayName(a.from), to), width) + "\n")
+		content.WriteString(statusStyle.Render("  "+truncate(a.content, width-2)) + "\n")
+	}
+	return content.String()
+}
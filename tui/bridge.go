@@ -0,0 +1,533 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bridgeMsg wraps a decoded bridge frame so the model knows to keep listening
+type bridgeMsg struct {
+	inner tea.Msg
+}
+
+// listenBridge waits for the next frame from the bridge and decodes it
+func listenBridge(c bridgeTransport) tea.Cmd {
+	return func() tea.Msg {
+		frame := <-c.Receive()
+		return bridgeMsg{inner: decodeBridgeMessage(frame)}
+	}
+}
+
+// Wire formats sent by the bridge. Timestamps are JavaScript epoch milliseconds.
+type wireEvent struct {
+	Timestamp int64       `json:"timestamp"`
+	Type      string      `json:"type"`
+	From      string      `json:"from"`
+	To        string      `json:"to"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data"`
+}
+
+// wireCausal finds Happen causal context either on the payload itself or nested under
+// "causal", "context.causal", "metadata" or an event's "data"
+type wireCausal struct {
+	ID            string      `json:"id"`
+	Sender        string      `json:"sender"`
+	Path          []string    `json:"path"`
+	CorrelationID string      `json:"correlationId"`
+	CausationID   string      `json:"causationId"`
+	Causal        *wireCausal `json:"causal"`
+	Context       *wireCausal `json:"context"`
+	Metadata      *wireCausal `json:"metadata"`
+	Data          *wireCausal `json:"data"`
+
+	// Span outcome reported by the sender
+	Duration   float64     `json:"duration"` // milliseconds
+	DurationMs float64     `json:"durationMs"`
+	Success    *bool       `json:"success"`
+	Error      interface{} `json:"error"`
+}
+
+// find returns the first nested context, searched breadth first, that satisfies ok
+func (c *wireCausal) find(ok func(*wireCausal) bool) *wireCausal {
+	queue := []*wireCausal{c}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == nil {
+			continue
+		}
+		if ok(next) {
+			return next
+		}
+		queue = append(queue, next.Causal, next.Context, next.Metadata, next.Data)
+	}
+	return nil
+}
+
+func (c *wireCausal) ids() (correlationID, causationID string) {
+	if found := c.find(func(n *wireCausal) bool { return n.CorrelationID != "" }); found != nil {
+		return found.CorrelationID, found.CausationID
+	}
+	if found := c.find(func(n *wireCausal) bool { return n.CausationID != "" }); found != nil {
+		return "", found.CausationID
+	}
+	return "", ""
+}
+
+// decodeCausal ignores type mismatches, e.g. a string "data", and keeps what did decode
+func decodeCausal(payload interface{}) *wireCausal {
+	var causal wireCausal
+	data, err := json.Marshal(payload)
+	if err == nil {
+		json.Unmarshal(data, &causal)
+	}
+	return &causal
+}
+
+func causalIDs(payload interface{}) (correlationID, causationID string) {
+	return decodeCausal(payload).ids()
+}
+
+// applySpan copies trace identity and span outcome from a raw event payload onto an event
+func applySpan(event *EventInfo, payload interface{}) {
+	causal := decodeCausal(payload)
+	event.CorrelationID, event.CausationID = causal.ids()
+
+	// The event's own ID sits with its causal context, or failing that on the payload or its metadata
+	context := causal.find(func(n *wireCausal) bool {
+		return n.ID != "" && (n.Sender != "" || n.CorrelationID != "" || n.CausationID != "" || len(n.Path) > 0)
+	})
+	switch {
+	case context != nil:
+		event.ID, event.Path = context.ID, context.Path
+		if event.From == "" {
+			event.From = context.Sender
+		}
+	case causal.ID != "":
+		event.ID = causal.ID
+	case causal.Metadata != nil:
+		event.ID = causal.Metadata.ID
+	}
+
+	if found := causal.find(func(n *wireCausal) bool { return n.DurationMs > 0 || n.Duration > 0 }); found != nil {
+		event.Duration = time.Duration(max(found.DurationMs, found.Duration) * float64(time.Millisecond))
+	}
+	event.Failed = strings.HasSuffix(event.Type, ":failed") || strings.HasSuffix(event.Type, ":error") ||
+		causal.find(func(n *wireCausal) bool { return n.failed() }) != nil
+}
+
+// failed reports success: false or a non-empty error
+func (c *wireCausal) failed() bool {
+	if c.Success != nil && !*c.Success {
+		return true
+	}
+	switch e := c.Error.(type) {
+	case nil:
+		return false
+	case string:
+		return e != ""
+	case bool:
+		return e
+	}
+	return true
+}
+
+type wireWorkflowStep struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Agent     string   `json:"agent"`
+	State     string   `json:"state"`
+	DependsOn []string `json:"dependsOn"`
+	StartedAt int64    `json:"startedAt"`
+	EndedAt   int64    `json:"endedAt"`
+}
+
+type wireWorkflow struct {
+	ID     string             `json:"id"`
+	Name   string             `json:"name"`
+	Status string             `json:"status"`
+	Agents []string           `json:"agents"`
+	Steps  []wireWorkflowStep `json:"steps"`
+}
+
+func (s wireWorkflowStep) toStep() WorkflowStep {
+	state := s.State
+	if state == "" {
+		state = stepPending
+	}
+	return WorkflowStep{
+		ID:        s.ID,
+		Name:      s.Name,
+		Agent:     s.Agent,
+		State:     state,
+		DependsOn: s.DependsOn,
+		StartedAt: fromMillis(s.StartedAt),
+		EndedAt:   fromMillis(s.EndedAt),
+	}
+}
+
+func (w wireWorkflow) toWorkflow() WorkflowInfo {
+	workflow := WorkflowInfo{
+		ID:     w.ID,
+		Name:   w.Name,
+		Status: w.Status,
+		Agents: w.Agents,
+	}
+	if workflow.Name == "" {
+		workflow.Name = w.ID
+	}
+	for _, step := range w.Steps {
+		workflow.applyStepUpdate(step.toStep())
+	}
+	return workflow
+}
+
+// wireAgent is a registry AgentProfile, which keeps its numbers under "performance"
+type wireAgent struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Status       string   `json:"status"`
+	Capabilities []string `json:"capabilities"`
+	Performance  struct {
+		TasksCompleted  int     `json:"tasksCompleted"`
+		SuccessRate     float64 `json:"successRate"`
+		AvgResponseTime float64 `json:"avgResponseTime"`
+	} `json:"performance"`
+}
+
+func (w wireAgent) toAgent() AgentInfo {
+	return AgentInfo{
+		ID:           w.ID,
+		Name:         w.Name,
+		Type:         w.Type,
+		Status:       w.Status,
+		Tasks:        w.Performance.TasksCompleted,
+		SuccessRate:  w.Performance.SuccessRate,
+		ResponseTime: w.Performance.AvgResponseTime,
+		Capabilities: w.Capabilities,
+	}
+}
+
+// decodeBridgeMessage converts a raw bridge frame into a control center message.
+// Unknown frame types decode to nil and are ignored.
+func decodeBridgeMessage(frame WSMessage) tea.Msg {
+	switch frame.Type {
+	case "agent:list", "registry:update":
+		var payload struct {
+			Agents []wireAgent `json:"agents"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil {
+			return nil
+		}
+		agents := make([]AgentInfo, len(payload.Agents))
+		for i, agent := range payload.Agents {
+			agents[i] = agent.toAgent()
+		}
+		return AgentRegistryUpdate{Agents: agents}
+
+	case "event", "event:captured":
+		var payload wireEvent
+		if decodePayload(frame.Payload, &payload) != nil {
+			return nil
+		}
+		event := payload.toEvent()
+		applySpan(&event, frame.Payload)
+		return EventStreamUpdate{Event: event, Raw: frame.Payload}
+
+	case "stats":
+		var payload SystemStats
+		if decodePayload(frame.Payload, &payload) != nil {
+			return nil
+		}
+		return SystemStatsUpdate{Stats: payload}
+
+	case "agent:heartbeat":
+		var payload struct {
+			AgentID   string `json:"agentId"`
+			Timestamp int64  `json:"timestamp"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		at := fromMillis(payload.Timestamp)
+		if at.IsZero() {
+			at = time.Now()
+		}
+		return AgentHeartbeatUpdate{AgentID: payload.AgentID, At: at}
+
+	case "task:completed":
+		var payload struct {
+			AgentID  string  `json:"agentId"`
+			Duration float64 `json:"duration"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		return TaskCompletedUpdate{
+			AgentID:  payload.AgentID,
+			Duration: time.Duration(payload.Duration * float64(time.Millisecond)),
+		}
+
+	case "task:accepted", "task:rejected":
+		var payload struct {
+			ID          string `json:"id"`
+			AgentID     string `json:"agentId"`
+			Description string `json:"description"`
+			Error       string `json:"error"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.ID == "" {
+			return nil
+		}
+		if frame.Type == "task:rejected" && payload.Error == "" {
+			payload.Error = "rejected"
+		}
+		return TaskAckUpdate{ID: payload.ID, AgentID: payload.AgentID, Description: payload.Description, Err: payload.Error}
+
+	case "state:history":
+		var payload struct {
+			AgentID   string        `json:"agentId"`
+			Snapshots []interface{} `json:"snapshots"`
+			Error     string        `json:"error"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		update := StateHistoryUpdate{AgentID: payload.AgentID}
+		if payload.Error != "" {
+			update.Err = errors.New(payload.Error)
+		}
+		for _, raw := range payload.Snapshots {
+			if snapshot, ok := parseStateSnapshot(raw); ok {
+				update.Snapshots = append(update.Snapshots, snapshot)
+			}
+		}
+		sortSnapshots(update.Snapshots)
+		return update
+
+	case "agent:policy":
+		var payload struct {
+			AgentPolicy
+			Error string `json:"error"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		if payload.AutonomyLevel == "" {
+			payload.AutonomyLevel = "supervised"
+		}
+		return AgentPolicyUpdate{Policy: payload.AgentPolicy, Err: payload.Error}
+
+	case "agent:decision":
+		var payload struct {
+			AgentID      string      `json:"agentId"`
+			DecisionType string      `json:"decisionType"`
+			Decision     interface{} `json:"decision"`
+			Confidence   float64     `json:"confidence"`
+			Timestamp    int64       `json:"timestamp"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		at := fromMillis(payload.Timestamp)
+		if at.IsZero() {
+			at = time.Now()
+		}
+		decision, ok := payload.Decision.(string)
+		if !ok && payload.Decision != nil {
+			if data, err := json.Marshal(payload.Decision); err == nil {
+				decision = string(data)
+			}
+		}
+		return AgentDecisionUpdate{Decision: AgentDecision{
+			AgentID:      payload.AgentID,
+			DecisionType: payload.DecisionType,
+			Decision:     decision,
+			Confidence:   payload.Confidence,
+			At:           at,
+		}}
+
+	case "human:request":
+		var payload struct {
+			ID        string      `json:"id"`
+			Type      string      `json:"type"`
+			Priority  string      `json:"priority"`
+			From      string      `json:"from"`
+			Context   interface{} `json:"context"`
+			Options   []string    `json:"options"`
+			Timestamp int64       `json:"timestamp"`
+			Timeout   int64       `json:"timeout"`
+		}
+		var raw map[string]interface{}
+		if decodePayload(frame.Payload, &payload) != nil || decodePayload(frame.Payload, &raw) != nil || payload.ID == "" {
+			return nil
+		}
+		at := fromMillis(payload.Timestamp)
+		if at.IsZero() {
+			at = time.Now()
+		}
+		return HumanRequestUpdate{Request: &HumanRequest{
+			ID:       payload.ID,
+			Type:     payload.Type,
+			Priority: payload.Priority,
+			From:     payload.From,
+			Context:  payload.Context,
+			Options:  payload.Options,
+			At:       at,
+			Timeout:  time.Duration(payload.Timeout) * time.Millisecond,
+			raw:      raw,
+		}}
+
+	case "agent:notification":
+		var payload struct {
+			AgentID           string `json:"agentId"`
+			NotificationLevel string `json:"notificationLevel"`
+			PendingRequests   int    `json:"pendingRequests"`
+			Message           string `json:"message"`
+			RequestID         string `json:"requestId"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		level := statusNormal
+		switch payload.NotificationLevel {
+		case "notification":
+			level = statusNotification
+		case "critical":
+			level = statusCritical
+		}
+		correlationID, causationID := causalIDs(frame.Payload)
+		return notificationMsg{
+			agentId:           payload.AgentID,
+			notificationLevel: level,
+			pendingRequests:   payload.PendingRequests,
+			message:           payload.Message,
+			requestID:         payload.RequestID,
+			correlationID:     correlationID,
+			causationID:       causationID,
+		}
+
+	case "agent:spawn":
+		var payload struct {
+			AgentID string `json:"agentId"`
+			Role    struct {
+				Name string `json:"name"`
+			} `json:"role"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		name := payload.Role.Name
+		if name == "" {
+			name = payload.AgentID
+		}
+		return agentSpawnMsg{agentId: payload.AgentID, name: name}
+
+	case "agent:response":
+		var payload struct {
+			AgentID string      `json:"agentId"`
+			Content interface{} `json:"content"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.AgentID == "" {
+			return nil
+		}
+		correlationID, causationID := causalIDs(frame.Payload)
+		content := backgroundContent(payload.Content)
+		if _, ok := payload.Content.(string); !ok {
+			content = "```json\n" + content + "\n```"
+		}
+		return agentReplyMsg{
+			agentId:       payload.AgentID,
+			content:       content,
+			correlationID: correlationID,
+			causationID:   causationID,
+		}
+
+	case "agent:background":
+		var payload struct {
+			From      string      `json:"from"`
+			To        string      `json:"to"`
+			Type      string      `json:"type"`
+			Content   interface{} `json:"content"`
+			Timestamp int64       `json:"timestamp"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.From == "" {
+			return nil
+		}
+		at := time.Now()
+		if payload.Timestamp > 0 {
+			at = time.UnixMilli(payload.Timestamp)
+		}
+		correlationID, causationID := causalIDs(frame.Payload)
+		return backgroundMsg{activity: backgroundActivity{
+			at:            at,
+			from:          payload.From,
+			to:            payload.To,
+			kind:          payload.Type,
+			content:       backgroundContent(payload.Content),
+			correlationID: correlationID,
+			causationID:   causationID,
+		}}
+
+	case "workflow:update":
+		var payload wireWorkflow
+		if decodePayload(frame.Payload, &payload) != nil || payload.ID == "" {
+			return nil
+		}
+		return WorkflowUpdate{Workflow: payload.toWorkflow()}
+
+	case "workflow:step":
+		var payload struct {
+			WorkflowID string           `json:"workflowId"`
+			Step       wireWorkflowStep `json:"step"`
+		}
+		if decodePayload(frame.Payload, &payload) != nil || payload.WorkflowID == "" {
+			return nil
+		}
+		return WorkflowStepUpdate{WorkflowID: payload.WorkflowID, Step: payload.Step.toStep()}
+	}
+
+	return nil
+}
+
+func (e wireEvent) toEvent() EventInfo {
+	ts := fromMillis(e.Timestamp)
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	message := e.Message
+	if message == "" && e.Data != nil {
+		if data, err := json.Marshal(e.Data); err == nil {
+			message = string(data)
+		}
+	}
+	return EventInfo{
+		Timestamp: ts.Format("15:04:05"),
+		At:        ts,
+		Type:      e.Type,
+		From:      e.From,
+		To:        e.To,
+		Message:   message,
+	}
+}
+
+// decodePayload re-marshals a generic JSON payload into a typed struct
+func decodePayload(payload interface{}, v interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func fromMillis(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// bridgeFrame parses a frame as the Node bridge writes it
+func bridgeFrame(t *testing.T, raw string) WSMessage {
+	t.Helper()
+	var frame WSMessage
+	if err := json.Unmarshal([]byte(raw), &frame); err != nil {
+		t.Fatal(err)
+	}
+	return frame
+}
+
+func TestDecodeStats(t *testing.T) {
+	// ControlCenterCabal.getSystemStats() after a few tasks
+	frame := bridgeFrame(t, `{"type":"stats","payload":{"totalAgents":3,"onlineAgents":2,"tasksCompleted":12,"eventsPerMinute":40,"avgResponseTime":850.5,"successRate":0.75}}`)
+	update, ok := decodeBridgeMessage(frame).(SystemStatsUpdate)
+	if !ok {
+		t.Fatalf("decoded %T", decodeBridgeMessage(frame))
+	}
+	want := SystemStats{TotalAgents: 3, OnlineAgents: 2, TasksCompleted: 12, EventsPerMinute: 40, AvgResponseTime: 850.5, SuccessRate: 0.75}
+	if update.Stats != want {
+		t.Errorf("stats = %+v, want %+v", update.Stats, want)
+	}
+
+	// Before any task finishes the bridge leaves out the averages
+	frame = bridgeFrame(t, `{"type":"stats","payload":{"totalAgents":3,"onlineAgents":3,"tasksCompleted":0,"eventsPerMinute":5}}`)
+	update = decodeBridgeMessage(frame).(SystemStatsUpdate)
+	if update.Stats.TotalAgents != 3 || update.Stats.TasksCompleted != 0 || update.Stats.SuccessRate != 0 {
+		t.Errorf("idle stats = %+v", update.Stats)
+	}
+}
+
+func TestDecodeRegistryProfiles(t *testing.T) {
+	// AgentRegistry.getAllAgents() entries as the bridge sends them in agent:list
+	frame := bridgeFrame(t, `{"type":"agent:list","payload":{"agents":[{"id":"agent-7","name":"researcher","type":"research","status":"busy","capabilities":["research","analysis"],"performance":{"tasksCompleted":9,"avgResponseTime":1500,"successRate":0.88,"lastSeen":1717171717171},"metadata":{"nodeId":"researcher","version":"1.0.0","startTime":1717171700000,"autonomyLevel":"supervised"}}]}}`)
+	update, ok := decodeBridgeMessage(frame).(AgentRegistryUpdate)
+	if !ok || len(update.Agents) != 1 {
+		t.Fatalf("decoded %#v", decodeBridgeMessage(frame))
+	}
+	got := update.Agents[0]
+	if got.ID != "agent-7" || got.Name != "researcher" || got.Status != "busy" || len(got.Capabilities) != 2 {
+		t.Errorf("agent = %+v", got)
+	}
+	if got.Tasks != 9 || got.SuccessRate != 0.88 || got.ResponseTime != 1500 {
+		t.Errorf("performance = %d tasks, %v success, %v ms, want 9, 0.88, 1500", got.Tasks, got.SuccessRate, got.ResponseTime)
+	}
+}
+
+func TestCapturedEventsReachTopology(t *testing.T) {
+	// ControlCenterCabal events for a peer message and its reply
+	frames := []string{
+		`{"type":"event:captured","payload":{"timestamp":1717171717000,"type":"agent:communication","from":"researcher","to":"coder","data":{"from":"researcher","to":"coder","type":"communication","content":"found it"}}}`,
+		`{"type":"event:captured","payload":{"timestamp":1717171718000,"type":"agent:communication","from":"coder","to":"researcher","data":{"from":"coder","to":"researcher","type":"communication","content":"thanks"}}}`,
+	}
+	m := initialControlCenterModel()
+	for _, raw := range frames {
+		next, _ := m.Update(decodeBridgeMessage(bridgeFrame(t, raw)))
+		m = next.(controlCenterModel)
+	}
+
+	edges := m.topology.edges(time.UnixMilli(1717171718000), time.Minute)
+	if len(edges) != 1 {
+		t.Fatalf("edges = %+v, want researcher <-> coder", edges)
+	}
+}
+
+func TestCapturedEventsFormTraces(t *testing.T) {
+	// The reply carries the message's correlationId and names it as its cause
+	frames := []string{
+		`{"type":"event:captured","payload":{"timestamp":1717171717000,"type":"agent:communication","from":"researcher","to":"coder","data":{"content":"found it"},"metadata":{"id":"e1","correlationId":"e1"}}}`,
+		`{"type":"event:captured","payload":{"timestamp":1717171718000,"type":"agent:communication","from":"coder","to":"researcher","data":{"content":"thanks"},"metadata":{"id":"e2","correlationId":"e1","causationId":"e1"}}}`,
+	}
+	m := initialControlCenterModel()
+	for _, raw := range frames {
+		next, _ := m.Update(decodeBridgeMessage(bridgeFrame(t, raw)))
+		m = next.(controlCenterModel)
+	}
+
+	if len(m.traces.order) != 1 {
+		t.Fatalf("%d traces, want the reply in the message's trace", len(m.traces.order))
+	}
+	spans := m.traces.order[0].spans()
+	if len(spans) != 2 || spans[1].depth != 1 || spans[1].event.From != "coder" {
+		t.Errorf("spans = %+v", spans)
+	}
+	if got := spans[0].duration(); got != time.Second {
+		t.Errorf("message span lasts %v, want 1s to the reply", got)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders values as a single row of block characters; NaN values are left blank
+func sparkline(values []float64) string {
+	lo, hi := seriesRange(values)
+	var out strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) {
+			out.WriteRune(' ')
+			continue
+		}
+		idx := 0
+		if hi > lo {
+			idx = int((v - lo) / (hi - lo) * float64(len(sparkRunes)-1))
+		}
+		out.WriteRune(sparkRunes[idx])
+	}
+	return out.String()
+}
+
+// Braille dot bits indexed by [row][column] within a 2x4 cell
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleChart draws a line chart using braille cells, two points wide and four high per character.
+// values are resampled to fit the width; the y-axis is labelled with the series range.
+func brailleChart(values []float64, width, height int, format string) string {
+	if width < 12 || height < 2 {
+		return sparkline(resample(values, max(width, 1)))
+	}
+
+	lo, hi := seriesRange(values)
+	labelWidth := max(len(fmt.Sprintf(format, hi)), len(fmt.Sprintf(format, lo)))
+	plotWidth := width - labelWidth - 2
+	if plotWidth < 4 {
+		return sparkline(resample(values, max(width, 1)))
+	}
+	points := resample(values, plotWidth*2)
+	rows := height * 4
+
+	grid := make([][]rune, height)
+	for y := range grid {
+		grid[y] = make([]rune, plotWidth)
+	}
+	plot := func(x, y int) {
+		if x < 0 || y < 0 || x >= plotWidth*2 || y >= rows {
+			return
+		}
+		row := rows - 1 - y
+		grid[row/4][x/2] |= brailleDots[row%4][x%2]
+	}
+	scale := func(v float64) int {
+		if hi == lo {
+			return rows / 2
+		}
+		return int(math.Round((v - lo) / (hi - lo) * float64(rows-1)))
+	}
+
+	prev := -1
+	for x, v := range points {
+		if math.IsNaN(v) {
+			prev = -1
+			continue
+		}
+		y := scale(v)
+		plot(x, y)
+		// Fill the vertical gap to the previous point so the line stays connected
+		if prev >= 0 {
+			for step := min(prev, y) + 1; step < max(prev, y); step++ {
+				plot(x, step)
+			}
+		}
+		prev = y
+	}
+
+	var out strings.Builder
+	for y, row := range grid {
+		label := ""
+		switch y {
+		case 0:
+			label = fmt.Sprintf(format, hi)
+		case height - 1:
+			label = fmt.Sprintf(format, lo)
+		}
+		out.WriteString(statusStyle.Render(fmt.Sprintf("%*s ┤", labelWidth, label)))
+		for _, cell := range row {
+			out.WriteRune(0x2800 + cell)
+		}
+		if y < height-1 {
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
+
+// resample stretches or averages values into exactly n points
+func resample(values []float64, n int) []float64 {
+	out := make([]float64, n)
+	if len(values) == 0 {
+		for i := range out {
+			out[i] = math.NaN()
+		}
+		return out
+	}
+	for i := range out {
+		start := i * len(values) / n
+		end := max((i+1)*len(values)/n, start+1)
+		total, count := 0.0, 0
+		for _, v := range values[start:min(end, len(values))] {
+			if !math.IsNaN(v) {
+				total += v
+				count++
+			}
+		}
+		if count == 0 {
+			out[i] = math.NaN()
+		} else {
+			out[i] = total / float64(count)
+		}
+	}
+	return out
+}
+
+func seriesRange(values []float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 0
+	}
+	return lo, hi
+}
+
+// lastValue returns the most recent non-NaN value of a series
+func lastValue(values []float64) (float64, bool) {
+	for i := len(values) - 1; i >= 0; i-- {
+		if !math.IsNaN(values[i]) {
+			return values[i], true
+		}
+	}
+	return 0, false
+}
@@ -3,13 +3,17 @@ package main
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/cabal/tui/happen"
 )
 
 // Tab modes
@@ -20,9 +24,13 @@ const (
 	tabEvents
 	tabWorkflows
 	tabAnalytics
+	tabAlerts
+	tabTopology
+	tabRequests
+	tabTraces
 )
 
-var tabNames = []string{"Agents", "Events", "Workflows", "Analytics"}
+var tabNames = []string{"Agents", "Events", "Workflows", "Analytics", "Alerts", "Topology", "Requests", "Traces"}
 
 // Styles for control center
 var (
@@ -71,12 +79,80 @@ type controlCenterModel struct {
 	width        int
 	height       int
 	ready        bool
-	wsClient     *WSClient
+	wsClient     bridgeTransport
 	
 	// Tab content
 	agentTable   table.Model
 	eventView    viewport.Model
 	workflowList list.Model
+	workflowDetail viewport.Model
+	showWorkflowDetail bool
+	showWorkflowGraph bool
+	dagView      dagView
+	workflowPrompt textinput.Model
+	promptingWorkflow bool
+	workflowErrors []string
+	
+	// New task form, open while non-nil
+	taskForm *taskForm
+	
+	// Autonomy policies, reported decisions and the policy editor
+	policies        map[string]AgentPolicy
+	policyEdits     map[string][]policyEdit
+	pendingPolicies map[string]policyEdit // pushed to the bridge and not yet echoed back
+	decisions       []AgentDecision
+	policyForm      *policyForm
+	
+	// Temporal state browser for one agent, open while non-nil
+	stateBrowser *stateBrowser
+	
+	// Human requests and local auto-approval rules
+	humanRequests []*HumanRequest
+	requestCursor int
+	requestError  string
+	autoRules     []*AutoRule
+	autoRulesFile string
+	autoDecisions []*autoDecision
+	rulePrompt    textinput.Model
+	promptingRule bool
+	
+	// Audit log of sent human responses
+	audit          *auditLog
+	operator       string
+	showAudit      bool
+	auditFilter    textinput.Model
+	promptingAudit bool
+	auditNotice    string
+	sla            *slaTracker
+	
+	// External notification sinks
+	notifier *notifyDispatcher
+	
+	// Traces built from the causal context of stream events
+	traces      *traceStore
+	traceCursor int
+	openTrace   *trace
+	spanCursor  int
+	
+	// Event integrity checks and the Events tab filter on them
+	integrity       *integrityVerifier
+	integrityFilter int
+	
+	// JetStream history replay in the Events and Analytics tabs
+	replay          *replayState
+	replayPrompt    textinput.Model
+	promptingReplay bool
+	replayNotice    string
+	
+	// Flow balance of Happen's JetStream consumers
+	flow *flowHealth
+	
+	// Event type filter, written in the pattern language of Happen's node.on()
+	typeMatcher     happen.Matcher
+	typePattern     string
+	typePrompt      textinput.Model
+	promptingType   bool
+	
 	analyticsView viewport.Model
 	
 	// Data
@@ -84,6 +160,28 @@ type controlCenterModel struct {
 	events       []EventInfo
 	workflows    []WorkflowInfo
 	stats        SystemStats
+	history      *metricsHistory
+	latency      *latencyTracker
+	alerts       *alertEngine
+	alertCursor  int
+	heartbeats   *heartbeatTracker
+	topology     *commGraph
+	eventLog     *eventStore
+	eventFilter  *eventFilter
+	
+	// Topology selection
+	topologyCursor int
+	topologyWindow int
+	
+	// Analytics chart selection
+	chartWindow    int
+	chartMetric    int
+	analyticsAgent string
+	
+	// Analytics sub-view and heatmap selection
+	analyticsMode int
+	heatmapRow    int
+	heatmapCol    int
 }
 
 type AgentInfo struct {
@@ -99,10 +197,23 @@ type AgentInfo struct {
 
 type EventInfo struct {
 	Timestamp string
+	At        time.Time
 	Type      string
 	From      string
 	To        string
 	Message   string
+
+	// Happen causal context, used to build traces
+	ID            string
+	CorrelationID string
+	CausationID   string
+	Path          []string
+	Duration      time.Duration
+	Failed        bool
+
+	// EventIntegrity check result: verified, unverified or tampered
+	Integrity     string
+	IntegrityNote string
 }
 
 type WorkflowInfo struct {
@@ -110,6 +221,7 @@ type WorkflowInfo struct {
 	Name   string
 	Status string
 	Agents []string
+	Steps  []WorkflowStep
 }
 
 type SystemStats struct {
@@ -130,6 +242,10 @@ func initialControlCenterModel() controlCenterModel {
 		{Title: "Tasks", Width: 8},
 		{Title: "Success", Width: 10},
 		{Title: "Avg Time", Width: 10},
+		{Title: "p50", Width: 8},
+		{Title: "p90", Width: 8},
+		{Title: "p99", Width: 8},
+		{Title: "Last Seen", Width: 10},
 		{Title: "Capabilities", Width: 30},
 	}
 	
@@ -155,22 +271,78 @@ func initialControlCenterModel() controlCenterModel {
 	// Create other views
 	eventView := viewport.New(80, 20)
 	workflowList := list.New([]list.Item{}, list.NewDefaultDelegate(), 40, 20)
+	workflowList.SetShowTitle(false)
+	workflowList.SetShowStatusBar(false)
+	workflowDetail := viewport.New(80, 20)
+	workflowPrompt := textinput.New()
+	workflowPrompt.Placeholder = "path/to/workflow.json"
+	workflowPrompt.Prompt = "Definition file: "
 	analyticsView := viewport.New(80, 20)
+	rulePrompt := textinput.New()
+	rulePrompt.Prompt = "Rule: "
+	rulePrompt.Width = 80
+	auditFilter := textinput.New()
+	auditFilter.Prompt = "Filter: "
+	auditFilter.Placeholder = "agent, type, choice, operator or context text"
+	auditFilter.Width = 60
+	replayPrompt := textinput.New()
+	replayPrompt.Prompt = "Replay from: "
+	replayPrompt.Placeholder = "sequence, 8h, 02:00, 2006-01-02 15:04 or empty for the whole stream"
+	replayPrompt.Width = 70
+	typePrompt := textinput.New()
+	typePrompt.Prompt = "Event type: "
+	typePrompt.Placeholder = "order.created, order.*, {order,payment}.created or empty for all"
+	typePrompt.Width = 70
 	
 	return controlCenterModel{
 		activeTab:     tabAgents,
 		agentTable:    agentTable,
 		eventView:     eventView,
 		workflowList:  workflowList,
+		workflowDetail: workflowDetail,
+		dagView:       newDAGView(),
+		workflowPrompt: workflowPrompt,
 		analyticsView: analyticsView,
 		agents:        []AgentInfo{},
 		events:        []EventInfo{},
 		workflows:     []WorkflowInfo{},
+		history:       newMetricsHistory(),
+		latency:       newLatencyTracker(),
+		alerts:        newAlertEngine(mustDefaultAlertRules()),
+		heartbeats:    newHeartbeatTracker(defaultStaleTTL, defaultOfflineTTL),
+		topology:      newCommGraph(),
+		eventLog:      &eventStore{},
+		policies:      make(map[string]AgentPolicy),
+		policyEdits:   make(map[string][]policyEdit),
+		pendingPolicies: make(map[string]policyEdit),
+		rulePrompt:    rulePrompt,
+		audit:         &auditLog{},
+		operator:      currentOperator(),
+		auditFilter:   auditFilter,
+		replayPrompt:  replayPrompt,
+		typePrompt:    typePrompt,
+		sla:           newSLATracker(defaultSLATargets, defaultSLATarget),
+		traces:        newTraceStore(defaultTraceSlow),
+		integrity:     &integrityVerifier{},
+		flow:          newFlowHealth(),
 	}
 }
 
 func (m controlCenterModel) Init() tea.Cmd {
-	return nil
+	cmds := []tea.Cmd{controlCenterTick()}
+	if m.wsClient != nil {
+		cmds = append(cmds, listenBridge(m.wsClient), m.startFlowPolling())
+	}
+	return tea.Batch(cmds...)
+}
+
+type controlCenterTickMsg time.Time
+
+// controlCenterTick drives time-based refreshes such as running step durations
+func controlCenterTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return controlCenterTickMsg(t)
+	})
 }
 
 func (m controlCenterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -194,10 +366,104 @@ func (m controlCenterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 		m.workflowList.SetSize(contentWidth, contentHeight)
 		
+		m.workflowDetail.Width = contentWidth
+		m.workflowDetail.Height = contentHeight
+		
 		m.analyticsView.Width = contentWidth
 		m.analyticsView.Height = contentHeight
 		
+		m.resizeStateBrowser()
+		
 	case tea.KeyMsg:
+		// Text prompts take every key until they are submitted or cancelled
+		if m.taskForm != nil {
+			cmds = append(cmds, m.handleTaskFormKey(msg))
+			return m, tea.Batch(cmds...)
+		}
+		if m.policyForm != nil {
+			cmds = append(cmds, m.handlePolicyFormKey(msg))
+			return m, tea.Batch(cmds...)
+		}
+		if m.promptingAudit {
+			switch msg.String() {
+			case "esc":
+				m.auditFilter.SetValue("")
+				fallthrough
+			case "enter":
+				m.promptingAudit = false
+				m.auditFilter.Blur()
+			default:
+				var cmd tea.Cmd
+				m.auditFilter, cmd = m.auditFilter.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+		if m.promptingReplay {
+			switch msg.String() {
+			case "esc":
+				m.promptingReplay = false
+				m.replayPrompt.Blur()
+			case "enter":
+				m.promptingReplay = false
+				m.replayPrompt.Blur()
+				cmds = append(cmds, m.loadReplay(m.replayPrompt.Value()))
+			default:
+				var cmd tea.Cmd
+				m.replayPrompt, cmd = m.replayPrompt.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+		if m.promptingType {
+			switch msg.String() {
+			case "esc":
+				m.promptingType = false
+				m.typePrompt.Blur()
+			case "enter":
+				m.promptingType = false
+				m.typePrompt.Blur()
+				m.setTypePattern(m.typePrompt.Value())
+			default:
+				var cmd tea.Cmd
+				m.typePrompt, cmd = m.typePrompt.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+		if m.promptingRule {
+			switch msg.String() {
+			case "esc":
+				m.promptingRule = false
+				m.rulePrompt.Blur()
+			case "enter":
+				m.promptingRule = false
+				m.rulePrompt.Blur()
+				m.addAutoRule(strings.TrimSpace(m.rulePrompt.Value()))
+			default:
+				var cmd tea.Cmd
+				m.rulePrompt, cmd = m.rulePrompt.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+		if m.promptingWorkflow {
+			switch msg.String() {
+			case "esc":
+				m.promptingWorkflow = false
+				m.workflowPrompt.Blur()
+			case "enter":
+				m.promptingWorkflow = false
+				m.workflowPrompt.Blur()
+				m.submitWorkflowDefinition(strings.TrimSpace(m.workflowPrompt.Value()))
+			default:
+				var cmd tea.Cmd
+				m.workflowPrompt, cmd = m.workflowPrompt.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+			return m, tea.Batch(cmds...)
+		}
+		
 		// Global key handling
 		switch msg.String() {
 		case "ctrl+c", "q":
@@ -210,47 +476,245 @@ func (m controlCenterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.activeTab = tabWorkflows
 		case "4", "f4":
 			m.activeTab = tabAnalytics
+		case "5", "f5":
+			m.activeTab = tabAlerts
+		case "6", "f6":
+			m.activeTab = tabTopology
+		case "7", "f7":
+			m.activeTab = tabRequests
+		case "8", "f8":
+			m.activeTab = tabTraces
 		case "tab":
-			m.activeTab = (m.activeTab + 1) % 4
+			m.activeTab = (m.activeTab + 1) % tabMode(len(tabNames))
 		case "shift+tab":
-			m.activeTab = (m.activeTab + 3) % 4
+			m.activeTab = (m.activeTab + tabMode(len(tabNames)) - 1) % tabMode(len(tabNames))
 		}
 		
 		// Tab-specific key handling
 		switch m.activeTab {
 		case tabAgents:
 			var cmd tea.Cmd
-			m.agentTable, cmd = m.agentTable.Update(msg)
+			if m.stateBrowser != nil {
+				cmd = m.handleStateKey(msg)
+			} else if msg.String() == "s" && m.agentTable.Cursor() < len(m.agents) {
+				cmd = m.openStateBrowser(m.agents[m.agentTable.Cursor()])
+			} else if msg.String() == "enter" && m.agentTable.Cursor() < len(m.agents) {
+				// Chart the selected agent in the Analytics tab
+				m.analyticsAgent = m.agents[m.agentTable.Cursor()].ID
+				m.chartMetric = 0
+				m.activeTab = tabAnalytics
+				m.updateAnalyticsView()
+			} else if msg.String() == "t" {
+				cmd = m.openTaskForm()
+			} else if msg.String() == "p" && m.agentTable.Cursor() < len(m.agents) {
+				m.openPolicyForm(m.agents[m.agentTable.Cursor()])
+			} else {
+				m.agentTable, cmd = m.agentTable.Update(msg)
+			}
 			cmds = append(cmds, cmd)
 		case tabEvents:
 			var cmd tea.Cmd
-			m.eventView, cmd = m.eventView.Update(msg)
+			if used, replayCmd := m.handleReplayKey(msg.String()); used {
+				cmd = replayCmd
+			} else if msg.String() == "R" {
+				m.openReplayPrompt()
+			} else if msg.String() == "/" {
+				m.typePrompt.SetValue(m.typePattern)
+				m.typePrompt.Focus()
+				m.promptingType = true
+			} else if msg.String() == "x" && (m.eventFilter != nil || m.typeMatcher != nil) {
+				m.eventFilter = nil
+				m.setTypePattern("")
+			} else if msg.String() == "i" {
+				m.integrityFilter = (m.integrityFilter + 1) % integrityFilterCount
+				m.updateEventView()
+			} else if msg.String() == "v" {
+				m.integrity.strict = !m.integrity.strict
+				m.updateEventView()
+			} else {
+				m.eventView, cmd = m.eventView.Update(msg)
+			}
 			cmds = append(cmds, cmd)
 		case tabWorkflows:
 			var cmd tea.Cmd
-			m.workflowList, cmd = m.workflowList.Update(msg)
+			if m.showWorkflowDetail && m.showWorkflowGraph {
+				m.handleGraphKey(msg)
+			} else if m.showWorkflowDetail {
+				switch msg.String() {
+				case "esc", "backspace":
+					m.showWorkflowDetail = false
+				case "g":
+					m.showWorkflowGraph = true
+					m.dagView = newDAGView()
+				default:
+					m.workflowDetail, cmd = m.workflowDetail.Update(msg)
+				}
+			} else if msg.String() == "n" && !m.workflowList.SettingFilter() {
+				m.promptingWorkflow = true
+				m.workflowErrors = nil
+				cmd = m.workflowPrompt.Focus()
+			} else if msg.String() == "enter" && m.selectedWorkflow() != nil {
+				m.showWorkflowDetail = true
+				m.updateWorkflowDetail()
+				m.workflowDetail.GotoTop()
+			} else {
+				m.workflowList, cmd = m.workflowList.Update(msg)
+			}
 			cmds = append(cmds, cmd)
 		case tabAnalytics:
 			var cmd tea.Cmd
-			m.analyticsView, cmd = m.analyticsView.Update(msg)
+			switch msg.String() {
+			case "h":
+				m.analyticsMode = (m.analyticsMode + 1) % 2
+			case "w":
+				m.chartWindow = (m.chartWindow + 1) % len(chartWindows)
+			case "up", "down", "left", "right", "k", "j", "l", "enter":
+				if m.analyticsMode == analyticsHeatmap {
+					m.handleHeatmapKey(msg.String())
+				} else {
+					m.analyticsView, cmd = m.analyticsView.Update(msg)
+				}
+			case "m":
+				m.chartMetric++
+			case "a":
+				m.analyticsAgent = ""
+				m.chartMetric = 0
+			default:
+				m.analyticsView, cmd = m.analyticsView.Update(msg)
+			}
+			m.updateAnalyticsView()
 			cmds = append(cmds, cmd)
+		case tabAlerts:
+			m.handleAlertKey(msg.String())
+		case tabTopology:
+			m.handleTopologyKey(msg.String())
+		case tabRequests:
+			m.handleRequestKey(msg.String())
+		case tabTraces:
+			m.handleTraceKey(msg.String())
 		}
 		
+	case controlCenterTickMsg:
+		if m.showWorkflowDetail {
+			m.updateWorkflowDetail()
+		}
+		if m.activeTab == tabAnalytics {
+			m.updateAnalyticsView()
+		}
+		m.checkHeartbeats(time.Time(msg))
+		m.expireHumanRequests(time.Time(msg))
+		m.sendDueAutoResponses(time.Time(msg))
+		m.evaluateAlerts(time.Time(msg))
+		m.updateAgentTable()
+		cmds = append(cmds, controlCenterTick())
+		
 	// Handle WebSocket messages
+	case bridgeMsg:
+		cmds = append(cmds, listenBridge(m.wsClient))
+		if msg.inner != nil {
+			updated, cmd := m.Update(msg.inner)
+			return updated, tea.Batch(append(cmds, cmd)...)
+		}
+		
 	case AgentRegistryUpdate:
 		m.agents = msg.Agents
+		m.liveHistory().recordAgents(time.Now(), msg.Agents)
+		m.heartbeats.track(msg.Agents)
 		m.updateAgentTable()
 		
+	case AgentHeartbeatUpdate:
+		m.heartbeats.beat(msg.AgentID, msg.At)
+		m.checkHeartbeats(time.Now())
+		m.updateAgentTable()
+		
+	case replayLoadedMsg:
+		m.handleReplayLoaded(msg)
+		
+	case replayTickMsg:
+		cmds = append(cmds, m.handleReplayTick())
+		
 	case EventStreamUpdate:
-		m.events = append([]EventInfo{msg.Event}, m.events...)
-		if len(m.events) > 100 {
-			m.events = m.events[:100]
-		}
-		m.updateEventView()
+		event := msg.Event
+		m.flow.observeFlowReport(msg.Event.Type, msg.Raw, time.Now())
+		event.Integrity, event.IntegrityNote = m.integrity.check(msg.Raw)
+		m.addEvent(event)
+		
+	case flowPolledMsg:
+		cmds = append(cmds, m.handleFlowPolled(msg))
+		
+	case flowTickMsg:
+		cmds = append(cmds, m.startFlowPolling())
 		
 	case SystemStatsUpdate:
 		m.stats = msg.Stats
+		m.liveHistory().recordStats(time.Now(), msg.Stats)
 		m.updateAnalyticsView()
+		
+	case StateHistoryUpdate:
+		m.handleStateHistory(msg)
+		
+	case stateHistoryTimeoutMsg:
+		m.handleStateHistoryTimeout(msg)
+		
+	case AgentPolicyUpdate:
+		m.handlePolicyUpdate(msg)
+		
+	case HumanRequestUpdate:
+		m.addHumanRequest(msg.Request)
+		
+	case AgentDecisionUpdate:
+		m.recordDecision(msg.Decision)
+		
+	case notificationMsg:
+		now := time.Now()
+		m.sla.arrive(msg.requestID, now)
+		m.addEvent(EventInfo{
+			Timestamp: now.Format("15:04:05"),
+			At:        now,
+			Type:      "agent:notification",
+			From:      m.agentLabel(msg.agentId),
+			To:        "control-center",
+			Message:   fmt.Sprintf("%s %s", msg.notificationLevel, msg.message),
+		})
+		cmds = append(cmds, m.notifier.dispatch(msg.agentId, m.agentLabel(msg.agentId), msg.notificationLevel, msg.message, msg.pendingRequests, now))
+		
+	case sinkResultMsg:
+		m.notifier.record(msg)
+		if msg.err != nil {
+			now := time.Now()
+			m.addEvent(EventInfo{
+				Timestamp: now.Format("15:04:05"),
+				At:        now,
+				Type:      "notify:error",
+				From:      "control-center",
+				To:        msg.sink,
+				Message:   msg.err.Error(),
+			})
+		}
+		
+	case TaskAckUpdate:
+		m.handleTaskAck(msg)
+		
+	case TaskCompletedUpdate:
+		m.latency.record(msg.AgentID, msg.Duration)
+		m.updateAgentTable()
+		if m.activeTab == tabAnalytics {
+			m.updateAnalyticsView()
+		}
+		
+	case WorkflowUpdate:
+		m.upsertWorkflow(msg.Workflow)
+		m.updateWorkflowList()
+		if m.showWorkflowDetail {
+			m.updateWorkflowDetail()
+		}
+		
+	case WorkflowStepUpdate:
+		m.applyWorkflowStep(msg.WorkflowID, msg.Step)
+		m.updateWorkflowList()
+		if m.showWorkflowDetail {
+			m.updateWorkflowDetail()
+		}
 	}
 	
 	return m, tea.Batch(cmds...)
@@ -285,6 +749,14 @@ func (m controlCenterModel) View() string {
 		content = m.renderWorkflowsTab()
 	case tabAnalytics:
 		content = m.renderAnalyticsTab()
+	case tabAlerts:
+		content = m.renderAlertsTab()
+	case tabTopology:
+		content = m.renderTopologyTab()
+	case tabRequests:
+		content = m.renderRequestsTab()
+	case tabTraces:
+		content = m.renderTracesTab()
 	}
 	
 	// Status bar
@@ -303,32 +775,49 @@ func (m *controlCenterModel) renderAgentsTab() string {
 	title := titleStyle.Render("🤖 Agent Registry")
 	
 	// Quick stats
-	onlineCount := 0
-	for _, agent := range m.agents {
-		if agent.Status == "online" {
+	onlineCount, staleCount := 0, 0
+	for _, agent := range m.liveAgents(time.Now()) {
+		switch agent.Status {
+		case "online":
 			onlineCount++
+		case livenessStale:
+			staleCount++
 		}
 	}
 	
 	stats := fmt.Sprintf(
-		"%s %d  %s %d  %s %d",
+		"%s %d  %s %d  %s %d  %s %d",
 		statLabelStyle.Render("Total:"),
 		len(m.agents),
 		statLabelStyle.Render("Online:"),
 		onlineCount,
+		statLabelStyle.Render("Stale:"),
+		staleCount,
 		statLabelStyle.Render("Offline:"),
-		len(m.agents)-onlineCount,
+		len(m.agents)-onlineCount-staleCount,
 	)
 	
+	body := m.agentTable.View()
+	if m.taskForm != nil {
+		title = titleStyle.Render("📝 New Task")
+		body = m.renderTaskForm(m.width - 8)
+	} else if m.policyForm != nil {
+		title = titleStyle.Render("🔐 Policy: " + m.policyForm.agent.Name)
+		body = m.renderPolicyForm(m.width - 8)
+	} else if m.stateBrowser != nil {
+		title = titleStyle.Render("🕰  State History: " + m.stateBrowser.agentName)
+		body = m.renderStateBrowser()
+	}
+	
 	content := lipgloss.JoinVertical(
 		lipgloss.Left,
 		title,
 		stats,
 		"",
-		m.agentTable.View(),
+		body,
 	)
 	
-	return contentStyle.
+	return m.frameStyle().
 		Width(m.width - 4).
 		Height(m.height - 6).
 		Render(content)
@@ -336,14 +825,46 @@ func (m *controlCenterModel) renderAgentsTab() string {
 
 func (m *controlCenterModel) renderEventsTab() string {
 	title := titleStyle.Render("📡 Live Event Stream")
+	if m.replay != nil {
+		title = titleStyle.Render("⏺ Event Replay")
+	}
 	
-	return contentStyle.
+	filter := ""
+	if f := m.eventFilter; f != nil {
+		filter = statusStyle.Render(fmt.Sprintf(
+			"Filtered: %s %s–%s • x: clear",
+			f.agent,
+			f.from.Format("15:04:05"),
+			f.to.Format("15:04:05"),
+		))
+	}
+	if m.typeMatcher != nil {
+		filter = lipgloss.JoinVertical(lipgloss.Left, filter, statusStyle.Render(
+			fmt.Sprintf("Type: %s • /: edit • x: clear", m.typePattern)))
+	}
+	filter = lipgloss.JoinVertical(lipgloss.Left, filter, m.renderIntegrityLine())
+	if err := m.eventLog.err; err != nil {
+		filter = lipgloss.JoinVertical(lipgloss.Left, filter, offlineStyle.Render("Event log: "+err.Error()))
+	}
+	switch {
+	case m.promptingType:
+		filter = lipgloss.JoinVertical(lipgloss.Left, filter, m.typePrompt.View())
+	case m.promptingReplay:
+		filter = lipgloss.JoinVertical(lipgloss.Left, filter, m.replayPrompt.View())
+	case m.replayNotice != "":
+		filter = lipgloss.JoinVertical(lipgloss.Left, filter, statusStyle.Render(m.replayNotice))
+	}
+	if m.replay != nil {
+		filter = lipgloss.JoinVertical(lipgloss.Left, filter, m.renderReplayBar(m.width-8))
+	}
+	
+	return m.frameStyle().
 		Width(m.width - 4).
 		Height(m.height - 6).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Left,
 			title,
-			"",
+			filter,
 			m.eventView.View(),
 		))
 }
@@ -351,21 +872,44 @@ func (m *controlCenterModel) renderEventsTab() string {
 func (m *controlCenterModel) renderWorkflowsTab() string {
 	title := titleStyle.Render("🔄 Workflows")
 	
-	return contentStyle.
+	body := m.workflowList.View()
+	if workflow := m.selectedWorkflow(); m.showWorkflowDetail && m.showWorkflowGraph && workflow != nil {
+		body = lipgloss.JoinVertical(
+			lipgloss.Left,
+			renderWorkflowDAG(*workflow, m.dagView, m.width-8, m.height-12),
+			statusStyle.Render("←↑↓→/hjkl: pan • +/-: zoom • c: critical path • g: table • Esc: back"),
+		)
+	} else if m.showWorkflowDetail {
+		body = lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.workflowDetail.View(),
+			statusStyle.Render("g: graph • Esc: back to list"),
+		)
+	} else if m.promptingWorkflow {
+		body = lipgloss.JoinVertical(lipgloss.Left, m.workflowPrompt.View(), "", body)
+	} else if len(m.workflowErrors) > 0 {
+		errors := make([]string, len(m.workflowErrors))
+		for i, err := range m.workflowErrors {
+			errors[i] = offlineStyle.Render("✗ " + err)
+		}
+		body = lipgloss.JoinVertical(lipgloss.Left, append(errors, "", body)...)
+	}
+	
+	return m.frameStyle().
 		Width(m.width - 4).
 		Height(m.height - 6).
 		Render(lipgloss.JoinVertical(
 			lipgloss.Left,
 			title,
 			"",
-			m.workflowList.View(),
+			body,
 		))
 }
 
 func (m *controlCenterModel) renderAnalyticsTab() string {
 	title := titleStyle.Render("📊 System Analytics")
 	
-	return contentStyle.
+	return m.frameStyle().
 		Width(m.width - 4).
 		Height(m.height - 6).
 		Render(lipgloss.JoinVertical(
@@ -377,7 +921,7 @@ func (m *controlCenterModel) renderAnalyticsTab() string {
 }
 
 func (m *controlCenterModel) renderStatusBar() string {
-	help := "Tab/F1-F4: Switch • q: Quit"
+	help := fmt.Sprintf("Tab/F1-F%d: Switch • q: Quit", len(tabNames))
 	
 	status := fmt.Sprintf(
 		"🟢 %d agents • 📊 %d tasks/min • ⚡ %.2fms avg",
@@ -385,6 +929,9 @@ func (m *controlCenterModel) renderStatusBar() string {
 		m.stats.EventsPerMinute,
 		m.stats.AvgResponseTime,
 	)
+	if firing := len(m.alerts.firing()); firing > 0 {
+		status += fmt.Sprintf(" • 🚨 %d alerts", firing)
+	}
 	
 	width := m.width / 2
 	statusSection := statusStyle.Width(width).Align(lipgloss.Left).Render(status)
@@ -395,8 +942,9 @@ func (m *controlCenterModel) renderStatusBar() string {
 
 func (m *controlCenterModel) updateAgentTable() {
 	rows := []table.Row{}
+	now := time.Now()
 	
-	for _, agent := range m.agents {
+	for _, agent := range m.liveAgents(now) {
 		// Style status
 		var status string
 		switch agent.Status {
@@ -404,12 +952,13 @@ func (m *controlCenterModel) updateAgentTable() {
 			status = onlineStyle.Render("● " + agent.Status)
 		case "offline":
 			status = offlineStyle.Render("● " + agent.Status)
-		case "busy":
+		case "busy", livenessStale:
 			status = busyStyle.Render("● " + agent.Status)
 		default:
 			status = agent.Status
 		}
 		
+		p50, p90, p99 := m.latency.percentiles(agent.ID)
 		rows = append(rows, table.Row{
 			agent.Name,
 			agent.Type,
@@ -417,6 +966,10 @@ func (m *controlCenterModel) updateAgentTable() {
 			fmt.Sprintf("%d", agent.Tasks),
 			fmt.Sprintf("%.1f%%", agent.SuccessRate*100),
 			fmt.Sprintf("%.0fms", agent.ResponseTime),
+			p50,
+			p90,
+			p99,
+			m.heartbeats.lastSeenText(agent.ID, now),
 			strings.Join(agent.Capabilities, ", "),
 		})
 	}
@@ -424,25 +977,75 @@ func (m *controlCenterModel) updateAgentTable() {
 	m.agentTable.SetRows(rows)
 }
 
+func (m *controlCenterModel) addEvent(event EventInfo) {
+	m.topology.record(event)
+	m.traces.add(event)
+	m.eventLog.append(event)
+	if m.replay != nil {
+		// Keep the live stream aside until the replay is closed
+		m.replay.liveEvents = append([]EventInfo{event}, m.replay.liveEvents...)
+		if len(m.replay.liveEvents) > 100 {
+			m.replay.liveEvents = m.replay.liveEvents[:100]
+		}
+		return
+	}
+	m.events = append([]EventInfo{event}, m.events...)
+	if len(m.events) > 100 {
+		m.events = m.events[:100]
+	}
+	m.updateEventView()
+}
+
+// setTypePattern filters the Events tab to event types matching pattern, the way a Happen
+// node's on() handler would; an empty pattern shows every type
+func (m *controlCenterModel) setTypePattern(pattern string) {
+	m.typePattern = strings.TrimSpace(pattern)
+	m.typeMatcher = nil
+	if m.typePattern != "" {
+		m.typeMatcher = happen.NewMatcher(m.typePattern)
+	}
+	m.updateEventView()
+}
+
 func (m *controlCenterModel) updateEventView() {
 	var content strings.Builder
 	
-	for _, event := range m.events {
+	events := m.events
+	if f := m.eventFilter; f != nil {
+		events = m.eventLog.between(f)
+		if len(events) == 0 {
+			content.WriteString("No events for this agent in the selected window\n")
+		}
+	}
+	
+	for _, event := range events {
+		if !m.showIntegrity(event) || (m.typeMatcher != nil && !m.typeMatcher(event.Type)) {
+			continue
+		}
 		line := fmt.Sprintf(
-			"%s [%s] %s → %s: %s\n",
+			"%s %s [%s] %s → %s: %s",
+			integrityBadge(event.Integrity),
 			event.Timestamp,
 			event.Type,
 			event.From,
 			event.To,
 			event.Message,
 		)
-		content.WriteString(line)
+		if event.Integrity == integrityTampered {
+			line += offlineStyle.Render(" (" + event.IntegrityNote + ")")
+		}
+		content.WriteString(line + "\n")
 	}
 	
 	m.eventView.SetContent(content.String())
 }
 
 func (m *controlCenterModel) updateAnalyticsView() {
+	if m.analyticsMode == analyticsHeatmap {
+		m.analyticsView.SetContent("\n" + m.renderHeatmap(m.analyticsView.Width-4))
+		return
+	}
+	
 	content := fmt.Sprintf(`
 System Overview
 ===============
@@ -455,7 +1058,19 @@ Events/Minute:     %d
 
 Performance Trends
 ==================
-[Performance graphs would go here]
+%s
+
+Task Latency
+============
+%s
+
+Human Response SLA
+==================
+%s
+
+Flow Balance
+============
+%s
 
 Resource Usage
 ==============
@@ -467,6 +1082,10 @@ Resource Usage
 		m.stats.SuccessRate*100,
 		m.stats.AvgResponseTime,
 		m.stats.EventsPerMinute,
+		m.renderTrends(m.analyticsView.Width-4),
+		m.renderLatency(m.analyticsView.Width-4),
+		m.renderSLA(m.analyticsView.Width-4),
+		m.renderFlowHealth(m.analyticsView.Width-4),
 	)
 	
 	m.analyticsView.SetContent(content)
@@ -479,6 +1098,7 @@ type AgentRegistryUpdate struct {
 
 type EventStreamUpdate struct {
 	Event EventInfo
+	Raw   interface{} // the frame payload, kept for integrity checks
 }
 
 type SystemStatsUpdate struct {
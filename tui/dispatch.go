@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Weights of each component in a candidate's dispatch score
+const (
+	weightCapability = 0.35
+	weightSuccess    = 0.25
+	weightSpeed      = 0.20
+	weightStatus     = 0.10
+	weightType       = 0.10
+)
+
+// Task form fields
+const (
+	taskFieldDescription = iota
+	taskFieldCapabilities
+	taskFieldType
+	taskFieldCount
+)
+
+var selectedRowStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("229")).
+	Background(lipgloss.Color("57"))
+
+// taskForm collects a new task and the agent it should be dispatched to
+type taskForm struct {
+	inputs  [taskFieldCount]textinput.Model
+	focus   int
+	agentID string // highlighted candidate; empty lets the bridge pick
+	err     string
+
+	pending string // ID of the task sent and not yet accepted or rejected by the bridge
+}
+
+func newTaskForm() *taskForm {
+	form := &taskForm{}
+	prompts := [taskFieldCount]string{"Description:   ", "Capabilities:  ", "Prefer type:   "}
+	placeholders := [taskFieldCount]string{"what should be done", "comma separated, e.g. research, analysis", "optional agent type"}
+	for i := range form.inputs {
+		input := textinput.New()
+		input.Prompt = prompts[i]
+		input.Placeholder = placeholders[i]
+		input.Width = 60
+		form.inputs[i] = input
+	}
+	form.inputs[taskFieldDescription].Focus()
+	return form
+}
+
+func (f *taskForm) description() string {
+	return strings.TrimSpace(f.inputs[taskFieldDescription].Value())
+}
+
+func (f *taskForm) capabilities() []string {
+	var caps []string
+	for _, c := range strings.Split(f.inputs[taskFieldCapabilities].Value(), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			caps = append(caps, c)
+		}
+	}
+	return caps
+}
+
+func (f *taskForm) preferredType() string {
+	return strings.TrimSpace(f.inputs[taskFieldType].Value())
+}
+
+func (f *taskForm) focusField(field int) {
+	f.inputs[f.focus].Blur()
+	f.focus = (field + taskFieldCount) % taskFieldCount
+	f.inputs[f.focus].Focus()
+}
+
+// candidateScore is one agent's ranking with the breakdown that produced it
+type candidateScore struct {
+	agent      AgentInfo
+	capability float64
+	success    float64
+	speed      float64
+	status     float64
+	typeMatch  float64
+	total      float64
+	missing    []string
+}
+
+func (c candidateScore) eligible() bool {
+	return len(c.missing) == 0 && c.agent.Status != livenessOffline
+}
+
+func statusScore(status string) float64 {
+	switch status {
+	case "online":
+		return 1
+	case "busy":
+		return 0.5
+	case livenessStale:
+		return 0.25
+	}
+	return 0
+}
+
+// rankCandidates scores agents like the registry's findBestAgent: required capabilities gate
+// eligibility, then success rate over response time decides, with status and type as tie-breakers
+func rankCandidates(agents []AgentInfo, required []string, preferredType string) []candidateScore {
+	fastest := 0.0
+	for _, agent := range agents {
+		if agent.ResponseTime > 0 && (fastest == 0 || agent.ResponseTime < fastest) {
+			fastest = agent.ResponseTime
+		}
+	}
+
+	scores := make([]candidateScore, 0, len(agents))
+	for _, agent := range agents {
+		score := candidateScore{agent: agent, capability: 1, speed: 1, typeMatch: 1}
+		for _, c := range required {
+			if !containsString(agent.Capabilities, c) {
+				score.missing = append(score.missing, c)
+			}
+		}
+		if len(required) > 0 {
+			score.capability = float64(len(required)-len(score.missing)) / float64(len(required))
+		}
+		score.success = agent.SuccessRate
+		if agent.ResponseTime > 0 && fastest > 0 {
+			score.speed = fastest / agent.ResponseTime
+		}
+		score.status = statusScore(agent.Status)
+		if preferredType != "" && !strings.EqualFold(agent.Type, preferredType) {
+			score.typeMatch = 0
+		}
+		score.total = weightCapability*score.capability +
+			weightSuccess*score.success +
+			weightSpeed*score.speed +
+			weightStatus*score.status +
+			weightType*score.typeMatch
+		scores = append(scores, score)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].eligible() != scores[j].eligible() {
+			return scores[i].eligible()
+		}
+		return scores[i].total > scores[j].total
+	})
+	return scores
+}
+
+func (m *controlCenterModel) openTaskForm() tea.Cmd {
+	m.taskForm = newTaskForm()
+	return textinput.Blink
+}
+
+func (m *controlCenterModel) handleTaskFormKey(msg tea.KeyMsg) tea.Cmd {
+	form := m.taskForm
+	switch msg.String() {
+	case "esc":
+		m.taskForm = nil
+	case "tab":
+		form.focusField(form.focus + 1)
+	case "shift+tab":
+		form.focusField(form.focus - 1)
+	case "up":
+		m.moveCandidate(-1)
+	case "down":
+		m.moveCandidate(1)
+	case "enter":
+		m.submitTask()
+	default:
+		var cmd tea.Cmd
+		form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// taskCandidates ranks the agents for the form and returns the highlighted row: 0 for the
+// bridge, n for the nth candidate. The highlight follows its agent as the ranking changes and
+// falls back to the bridge if the agent is gone.
+func (m *controlCenterModel) taskCandidates() ([]candidateScore, int) {
+	form := m.taskForm
+	candidates := rankCandidates(m.liveAgents(time.Now()), form.capabilities(), form.preferredType())
+	for i, c := range candidates {
+		if form.agentID != "" && c.agent.ID == form.agentID {
+			return candidates, i + 1
+		}
+	}
+	form.agentID = ""
+	return candidates, 0
+}
+
+func (m *controlCenterModel) moveCandidate(delta int) {
+	candidates, row := m.taskCandidates()
+	row = max(0, min(len(candidates), row+delta))
+	m.taskForm.agentID = ""
+	if row > 0 {
+		m.taskForm.agentID = candidates[row-1].agent.ID
+	}
+}
+
+// submitTask sends the task to the selected candidate, or without an agent so the bridge picks
+func (m *controlCenterModel) submitTask() {
+	form := m.taskForm
+	if form.pending != "" {
+		return
+	}
+	if form.description() == "" {
+		form.err = "a description is required"
+		return
+	}
+
+	agentID := form.agentID
+	if agentID != "" {
+		candidates, row := m.taskCandidates()
+		if row == 0 {
+			form.err = "the selected agent has left; choose another"
+			return
+		}
+		if candidate := candidates[row-1]; !candidate.eligible() {
+			form.err = fmt.Sprintf("%s is not eligible for this task", candidate.agent.Name)
+			return
+		}
+	}
+
+	if m.wsClient == nil {
+		form.err = "not connected to the bridge"
+		return
+	}
+	id := fmt.Sprintf("task-%d", time.Now().UnixMilli())
+	if err := m.wsClient.Send("task:submit", map[string]interface{}{
+		"id":            id,
+		"description":   form.description(),
+		"capabilities":  form.capabilities(),
+		"preferredType": form.preferredType(),
+		"agentId":       agentID,
+	}); err != nil {
+		form.err = fmt.Sprintf("submit failed: %v", err)
+		return
+	}
+	form.err = ""
+	form.pending = id
+}
+
+// handleTaskAck logs a task once the bridge has accepted it and closes its form; a rejection
+// goes back to the form, or to the event stream if the form was closed meanwhile
+func (m *controlCenterModel) handleTaskAck(ack TaskAckUpdate) {
+	form := m.taskForm
+	waiting := form != nil && form.pending == ack.ID
+	now := time.Now()
+	event := EventInfo{
+		Timestamp: now.Format("15:04:05"),
+		At:        now,
+		Type:      "task:submitted",
+		From:      "control-center",
+		To:        m.agentName(ack.AgentID),
+		Message:   ack.Description,
+	}
+	switch {
+	case ack.Err != "" && waiting:
+		form.pending = ""
+		form.err = "rejected by the bridge: " + ack.Err
+		return
+	case ack.Err != "":
+		event.Type, event.To, event.Message = "task:rejected", "bridge", ack.Description+": "+ack.Err
+	case waiting:
+		m.taskForm = nil
+	}
+	m.addEvent(event)
+}
+
+func (m *controlCenterModel) renderTaskForm(width int) string {
+	form := m.taskForm
+	var content strings.Builder
+	for _, input := range form.inputs {
+		content.WriteString(input.View() + "\n")
+	}
+	if form.err != "" {
+		content.WriteString(offlineStyle.Render(form.err) + "\n")
+	}
+	if form.pending != "" {
+		content.WriteString(busyStyle.Render("Waiting for the bridge to accept the task…") + "\n")
+	}
+	content.WriteString("\n")
+
+	candidates, selected := m.taskCandidates()
+
+	header := fmt.Sprintf("  %-18s %6s  %5s %5s %5s %5s %5s  %s", "Candidate", "Score", "Caps", "Succ", "Speed", "Stat", "Type", "Notes")
+	content.WriteString(statLabelStyle.Render(header) + "\n")
+
+	auto := "  " + padRight("⚙ Let the bridge pick", 18)
+	if selected == 0 {
+		auto = selectedRowStyle.Render(truncate(auto, width))
+	}
+	content.WriteString(auto + "\n")
+
+	for i, c := range candidates {
+		notes := ""
+		switch {
+		case len(c.missing) > 0:
+			notes = "missing " + strings.Join(c.missing, ", ")
+		case c.agent.Status == livenessOffline:
+			notes = "offline"
+		}
+		row := fmt.Sprintf("  %-18s %6.2f  %5.2f %5.2f %5.2f %5.2f %5.2f  %s",
+			truncate(c.agent.Name, 18), c.total, c.capability, c.success, c.speed, c.status, c.typeMatch, notes)
+		row = truncate(row, width)
+		switch {
+		case selected == i+1:
+			row = selectedRowStyle.Render(row)
+		case !c.eligible():
+			row = statusStyle.Render(row)
+		}
+		content.WriteString(row + "\n")
+	}
+	if len(candidates) == 0 {
+		content.WriteString(statusStyle.Render("  No agents registered") + "\n")
+	}
+
+	content.WriteString("\n" + statusStyle.Render(fmt.Sprintf(
+		"Score = %.2f caps + %.2f success + %.2f speed + %.2f status + %.2f type",
+		weightCapability, weightSuccess, weightSpeed, weightStatus, weightType,
+	)))
+	content.WriteString("\n" + statusStyle.Render("Tab: next field • ↑/↓: choose agent • Enter: dispatch • Esc: cancel"))
+	return content.String()
+}
+
+// TaskAckUpdate is the bridge's answer to task:submit: task:accepted with the agent it went to,
+// or task:rejected with the reason
+type TaskAckUpdate struct {
+	ID          string
+	AgentID     string
+	Description string
+	Err         string
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+// recordingTransport keeps what the control center sends
+type recordingTransport struct {
+	sent []WSMessage
+}
+
+func (r *recordingTransport) Send(msgType string, payload interface{}) error {
+	r.sent = append(r.sent, WSMessage{Type: msgType, Payload: payload})
+	return nil
+}
+
+func (r *recordingTransport) Receive() <-chan WSMessage { return nil }
+func (r *recordingTransport) Close()                    {}
+
+func TestSubmitSendsToHighlightedAgent(t *testing.T) {
+	m := initialControlCenterModel()
+	transport := &recordingTransport{}
+	m.wsClient = transport
+	m.agents = []AgentInfo{
+		{ID: "a1", Name: "alpha", Status: "online", SuccessRate: 0.9},
+		{ID: "a2", Name: "beta", Status: "online", SuccessRate: 0.5},
+	}
+	m.openTaskForm()
+	m.taskForm.inputs[taskFieldDescription].SetValue("summarise the logs")
+	m.moveCandidate(1)
+	m.moveCandidate(1)
+	if m.taskForm.agentID != "a2" {
+		t.Fatalf("highlighted %q, want beta", m.taskForm.agentID)
+	}
+
+	// A registry update reorders the candidates before Enter
+	m.agents[0].SuccessRate, m.agents[1].SuccessRate = 0.1, 0.95
+	if _, row := m.taskCandidates(); row != 1 {
+		t.Errorf("beta shown on row %d, want it to follow to the top", row)
+	}
+	m.submitTask()
+	if len(transport.sent) != 1 {
+		t.Fatalf("sent %v, form error %q", transport.sent, m.taskForm.err)
+	}
+	if got := transport.sent[0].Payload.(map[string]interface{})["agentId"]; got != "a2" {
+		t.Errorf("task sent to %v, want a2", got)
+	}
+}
+
+func TestSubmitAfterAgentLeft(t *testing.T) {
+	m := initialControlCenterModel()
+	transport := &recordingTransport{}
+	m.wsClient = transport
+	m.agents = []AgentInfo{{ID: "a1", Name: "alpha", Status: "online"}}
+	m.openTaskForm()
+	m.taskForm.inputs[taskFieldDescription].SetValue("summarise the logs")
+	m.moveCandidate(1)
+
+	m.agents = nil
+	m.submitTask()
+	if len(transport.sent) != 0 || m.taskForm.err == "" {
+		t.Errorf("sent %v with error %q, want the submit refused", transport.sent, m.taskForm.err)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The log is compacted down to the retained events once it grows past this size, or past twice
+// its size after the last compaction if the retained events alone are larger
+const maxEventLogBytes = 64 << 20
+
+// eventStore retains the event stream beyond the live view, optionally persisted as JSON lines
+type eventStore struct {
+	events  []EventInfo // oldest first
+	path    string
+	file    *os.File
+	size    int64
+	compact int64 // size at which the log is next compacted
+	err     error // the latest write failure, cleared by the next successful write
+}
+
+// openEventStore loads events younger than historyRetention from path and appends new ones to it.
+// An empty path keeps history in memory only.
+func openEventStore(path string) (*eventStore, error) {
+	store := &eventStore{}
+	if path == "" {
+		return store, nil
+	}
+
+	if f, err := os.Open(path); err == nil {
+		cutoff := time.Now().Add(-historyRetention)
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var event EventInfo
+			if json.Unmarshal(scanner.Bytes(), &event) == nil && event.At.After(cutoff) {
+				store.events = append(store.events, event)
+			}
+		}
+		f.Close()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	store.path, store.file = path, f
+	if info, err := f.Stat(); err == nil {
+		store.size = info.Size()
+	}
+	store.compact = maxEventLogBytes
+	return store, nil
+}
+
+func (s *eventStore) append(event EventInfo) {
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	s.events = append(s.events, event)
+	s.events = trimSamples(s.events, event.At, func(e EventInfo) time.Time { return e.At })
+
+	if s.file == nil {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	n, err := s.file.Write(append(data, '\n'))
+	s.size += int64(n)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.err = nil
+	if s.size > s.compact {
+		if err := s.rewrite(); err != nil {
+			s.err = fmt.Errorf("compacting: %w", err)
+			s.compact = 2 * s.size
+		}
+	}
+}
+
+// rewrite replaces the log with the events still retained, dropping those older than
+// historyRetention, and carries on appending to the new file
+func (s *eventStore) rewrite() error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, event := range s.events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		w.Write(append(data, '\n'))
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	info, err := f.Stat()
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	appendFile, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	s.file.Close()
+	s.file = appendFile
+	s.size = info.Size()
+	s.compact = max(maxEventLogBytes, 2*s.size)
+	return nil
+}
+
+// between returns events matching the filter, latest received first
+func (s *eventStore) between(filter *eventFilter) []EventInfo {
+	var out []EventInfo
+	for i := len(s.events) - 1; i >= 0; i-- {
+		if filter.matches(s.events[i]) {
+			out = append(out, s.events[i])
+		}
+	}
+	return out
+}
+
+// recent returns up to n of the latest events, newest first
+func (s *eventStore) recent(n int) []EventInfo {
+	out := make([]EventInfo, 0, min(n, len(s.events)))
+	for i := len(s.events) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, s.events[i])
+	}
+	return out
+}
+
+func (s *eventStore) Close() {
+	if s.file != nil {
+		s.file.Close()
+	}
+}
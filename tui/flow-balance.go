@@ -0,0 +1,462 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Flow balance follows Happen's FlowBalanceMonitor (flow-balance/index.ts): consumer lag on the
+// HAPPEN_EVENTS stream is read from JetStream and run through the same pattern detectors. Nodes
+// that run the monitor themselves also publish what they find (node.down and system.down events
+// on happen.system.flow-balance.*), which is shown next to the local detection.
+
+// Thresholds from DEFAULT_FLOW_BALANCE_CONFIG
+const (
+	flowMinorLag       = 100
+	flowModerateLag    = 500
+	flowSevereLag      = 1000
+	flowCriticalLag    = 5000
+	flowMinAckRate     = 0.9
+	flowConsumerPrefix = "happen-node-"
+)
+
+const (
+	flowPollInterval = 5 * time.Second  // the monitor's default pollingInterval
+	flowReportTTL    = 30 * time.Second // how long a node's report stands without being repeated
+	flowAlertSource  = "flow-balance"
+)
+
+// flowMetrics is Happen's FlowMetrics
+type flowMetrics struct {
+	ConsumerLag      float64 `json:"consumerLag"`
+	MessagesWaiting  float64 `json:"messagesWaiting"`
+	ProcessingRate   float64 `json:"processingRate"`
+	AckRate          float64 `json:"ackRate"`
+	DeliveryFailures float64 `json:"deliveryFailures"`
+}
+
+// consumerFlow is one durable consumer's metrics and the node state the monitor derives from them
+type consumerFlow struct {
+	name    string
+	node    string
+	metrics flowMetrics
+	state   string // healthy, degraded or unhealthy
+}
+
+// flowPattern is Happen's FlowPattern
+type flowPattern struct {
+	kind          string // partition, node-failure, bottleneck or overload
+	severity      string // minor, moderate, severe or critical
+	confidence    float64
+	affectedNodes []string
+	metrics       flowMetrics
+	detectedAt    time.Time
+	reported      bool // published by a Happen node rather than detected here
+}
+
+func (p flowPattern) key() string {
+	return p.kind + "/" + strings.Join(p.affectedNodes, ",")
+}
+
+// escalates reports whether the pattern belongs on the alert list
+func (p flowPattern) escalates() bool {
+	return p.severity == "severe" || p.severity == "critical"
+}
+
+func flowSeverityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 4
+	case "severe":
+		return 3
+	case "moderate":
+		return 2
+	case "minor":
+		return 1
+	}
+	return 0
+}
+
+// flowHealth holds the latest consumer poll and node reports
+type flowHealth struct {
+	consumers []consumerFlow
+	detected  []flowPattern
+	reported  map[string]flowPattern
+	polledAt  time.Time
+	err       error
+}
+
+func newFlowHealth() *flowHealth {
+	return &flowHealth{reported: make(map[string]flowPattern)}
+}
+
+// patterns merges local detection with unexpired node reports, worst first
+func (f *flowHealth) patterns(now time.Time) []flowPattern {
+	patterns := append([]flowPattern(nil), f.detected...)
+	seen := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		seen[p.key()] = true
+	}
+	for key, p := range f.reported {
+		if now.Sub(p.detectedAt) > flowReportTTL {
+			delete(f.reported, key)
+			continue
+		}
+		if !seen[key] {
+			patterns = append(patterns, p)
+		}
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		if ri, rj := flowSeverityRank(patterns[i].severity), flowSeverityRank(patterns[j].severity); ri != rj {
+			return ri > rj
+		}
+		return patterns[i].key() < patterns[j].key()
+	})
+	return patterns
+}
+
+// alerts turns severe and critical patterns into alerts for the alert engine
+func (f *flowHealth) alerts(now time.Time) []*Alert {
+	var alerts []*Alert
+	for _, p := range f.patterns(now) {
+		if !p.escalates() {
+			continue
+		}
+		severity := severityWarning
+		if p.severity == "critical" {
+			severity = severityCritical
+		}
+		alerts = append(alerts, &Alert{
+			Rule: AlertRule{
+				Name:     "Flow " + p.kind,
+				Expr:     "flow-balance " + p.severity,
+				Severity: severity,
+			},
+			Subject: strings.Join(p.affectedNodes, ","),
+			Value:   fmt.Sprintf("lag %.0f, %.0f%% confidence", p.metrics.ConsumerLag, p.confidence*100),
+		})
+	}
+	return alerts
+}
+
+// jsConsumerInfo is the part of JetStream's consumer info the monitor reads
+type jsConsumerInfo struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Config  struct {
+		DurableName string `json:"durable_name"`
+	} `json:"config"`
+	Delivered struct {
+		ConsumerSeq float64 `json:"consumer_seq"`
+	} `json:"delivered"`
+	AckFloor struct {
+		ConsumerSeq float64 `json:"consumer_seq"`
+	} `json:"ack_floor"`
+	NumRedelivered float64 `json:"num_redelivered"`
+}
+
+// extractFlowMetrics mirrors the monitor: lag is delivered but unacknowledged messages and the
+// rate is averaged over the consumer's lifetime
+func extractFlowMetrics(info jsConsumerInfo, now time.Time) flowMetrics {
+	lag := info.Delivered.ConsumerSeq - info.AckFloor.ConsumerSeq
+	age := 1.0
+	if !info.Created.IsZero() {
+		age = max(1, now.Sub(info.Created).Seconds())
+	}
+	return flowMetrics{
+		ConsumerLag:      lag,
+		MessagesWaiting:  lag,
+		ProcessingRate:   info.Delivered.ConsumerSeq / age,
+		AckRate:          info.AckFloor.ConsumerSeq / max(1, info.Delivered.ConsumerSeq),
+		DeliveryFailures: info.NumRedelivered,
+	}
+}
+
+func flowNodeState(metrics flowMetrics) string {
+	switch {
+	case metrics.ConsumerLag >= flowCriticalLag:
+		return "unhealthy"
+	case metrics.ConsumerLag >= flowModerateLag, metrics.AckRate < flowMinAckRate:
+		return "degraded"
+	}
+	return "healthy"
+}
+
+// detectFlowPatterns runs the monitor's partition, node-failure, bottleneck and overload checks
+func detectFlowPatterns(consumers []consumerFlow, now time.Time) []flowPattern {
+	if len(consumers) == 0 {
+		return nil
+	}
+	var patterns []flowPattern
+	total := float64(len(consumers))
+
+	// A partition is several nodes lagging badly at once; overload is most nodes lagging a little
+	aggregate := func(kind string, minLag float64, minCount float64, severity func(avgLag, count float64) string) {
+		var nodes []string
+		var sum float64
+		for _, c := range consumers {
+			if c.metrics.ConsumerLag >= minLag {
+				nodes = append(nodes, c.node)
+				sum += c.metrics.ConsumerLag
+			}
+		}
+		count := float64(len(nodes))
+		if count == 0 || count < minCount {
+			return
+		}
+		avg := sum / count
+		patterns = append(patterns, flowPattern{
+			kind:          kind,
+			severity:      severity(avg, count),
+			confidence:    count / total,
+			affectedNodes: nodes,
+			metrics:       flowMetrics{ConsumerLag: avg, MessagesWaiting: avg},
+			detectedAt:    now,
+		})
+	}
+	aggregate("partition", flowSevereLag, 2, func(_, count float64) string {
+		if count >= total/2 {
+			return "critical"
+		}
+		return "severe"
+	})
+
+	for _, c := range consumers {
+		if c.metrics.ConsumerLag >= flowCriticalLag {
+			patterns = append(patterns, flowPattern{kind: "node-failure", severity: "critical", confidence: 0.9,
+				affectedNodes: []string{c.node}, metrics: c.metrics, detectedAt: now})
+		}
+	}
+	for _, c := range consumers {
+		if c.metrics.ConsumerLag >= flowModerateLag && c.metrics.ProcessingRate < 1 {
+			severity := "moderate"
+			if c.metrics.ConsumerLag >= flowSevereLag {
+				severity = "severe"
+			}
+			patterns = append(patterns, flowPattern{kind: "bottleneck", severity: severity, confidence: 0.8,
+				affectedNodes: []string{c.node}, metrics: c.metrics, detectedAt: now})
+		}
+	}
+
+	aggregate("overload", flowMinorLag, total*0.7, func(avg, _ float64) string {
+		if avg >= flowSevereLag {
+			return "severe"
+		}
+		return "moderate"
+	})
+	return patterns
+}
+
+type flowPolledMsg struct {
+	at        time.Time
+	consumers []consumerFlow
+	err       error
+}
+
+type flowTickMsg time.Time
+
+func flowTick() tea.Cmd {
+	return tea.Tick(flowPollInterval, func(t time.Time) tea.Msg { return flowTickMsg(t) })
+}
+
+// pollFlow lists the durable consumers on Happen's stream; ephemeral ones such as the replay
+// consumer are not nodes and are left out
+func pollFlow(c *NATSClient) tea.Cmd {
+	return func() tea.Msg {
+		var consumers []consumerFlow
+		for offset := 0; ; {
+			var page struct {
+				Total     int              `json:"total"`
+				Consumers []jsConsumerInfo `json:"consumers"`
+			}
+			if err := jsRequest(c.nc, "$JS.API.CONSUMER.LIST."+happenStream,
+				map[string]interface{}{"offset": offset}, &page); err != nil {
+				return flowPolledMsg{at: time.Now(), err: err}
+			}
+			now := time.Now()
+			for _, info := range page.Consumers {
+				if info.Config.DurableName == "" {
+					continue
+				}
+				metrics := extractFlowMetrics(info, now)
+				consumers = append(consumers, consumerFlow{
+					name:    info.Name,
+					node:    strings.Replace(info.Name, flowConsumerPrefix, "", 1),
+					metrics: metrics,
+					state:   flowNodeState(metrics),
+				})
+			}
+			offset += len(page.Consumers)
+			if len(page.Consumers) == 0 || offset >= page.Total {
+				break
+			}
+		}
+		sort.Slice(consumers, func(i, j int) bool {
+			if consumers[i].metrics.ConsumerLag != consumers[j].metrics.ConsumerLag {
+				return consumers[i].metrics.ConsumerLag > consumers[j].metrics.ConsumerLag
+			}
+			return consumers[i].name < consumers[j].name
+		})
+		return flowPolledMsg{at: time.Now(), consumers: consumers}
+	}
+}
+
+// startFlowPolling begins polling when the control center reads the mesh over NATS
+func (m *controlCenterModel) startFlowPolling() tea.Cmd {
+	if c, ok := m.wsClient.(*NATSClient); ok {
+		return pollFlow(c)
+	}
+	return nil
+}
+
+func (m *controlCenterModel) handleFlowPolled(msg flowPolledMsg) tea.Cmd {
+	m.flow.polledAt, m.flow.err = msg.at, msg.err
+	if msg.err == nil {
+		m.flow.consumers = msg.consumers
+		m.flow.detected = detectFlowPatterns(msg.consumers, msg.at)
+	}
+	m.updateAnalyticsView()
+	return flowTick()
+}
+
+// flowReport is the payload of the node.down and system.down events the monitor emits
+type flowReport struct {
+	NodeID        string       `json:"nodeId"`
+	AffectedNodes []string     `json:"affectedNodes"`
+	Pattern       string       `json:"pattern"`
+	Severity      string       `json:"severity"`
+	Level         string       `json:"level"`
+	Confidence    float64      `json:"confidence"`
+	LagMetrics    *flowMetrics `json:"lagMetrics"`
+	Metrics       *flowMetrics `json:"metrics"`
+}
+
+// observeFlowReport records a node's flow-balance finding carried by a stream event
+func (f *flowHealth) observeFlowReport(eventType string, raw interface{}, at time.Time) {
+	if eventType != "node.down" && eventType != "system.down" {
+		return
+	}
+	queue := []interface{}{raw}
+	for len(queue) > 0 {
+		node, ok := queue[0].(map[string]interface{})
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		if _, ok := node["pattern"].(string); !ok {
+			queue = append(queue, node["data"], node["event"], node["payload"])
+			continue
+		}
+		var report flowReport
+		if decodePayload(node, &report) != nil {
+			return
+		}
+		p := flowPattern{
+			kind:          report.Pattern,
+			severity:      report.Severity,
+			confidence:    report.Confidence,
+			affectedNodes: report.AffectedNodes,
+			detectedAt:    at,
+			reported:      true,
+		}
+		if p.severity == "" {
+			p.severity = report.Level
+		}
+		if report.NodeID != "" {
+			p.affectedNodes = []string{report.NodeID}
+		}
+		switch {
+		case report.LagMetrics != nil:
+			p.metrics = *report.LagMetrics
+		case report.Metrics != nil:
+			p.metrics = *report.Metrics
+		}
+		f.reported[p.key()] = p
+		return
+	}
+}
+
+// renderFlowHealth shows per-consumer lag bars and the detected patterns
+func (m *controlCenterModel) renderFlowHealth(width int) string {
+	now := time.Now()
+	patterns := m.flow.patterns(now)
+	_, overNATS := m.wsClient.(*NATSClient)
+	if !overNATS && len(patterns) == 0 {
+		return statusStyle.Render("No flow-balance reports yet; connect with -bridge nats://… to read consumer lag")
+	}
+
+	var content strings.Builder
+	switch {
+	case m.flow.err != nil:
+		content.WriteString(offlineStyle.Render("Consumer poll failed: "+m.flow.err.Error()) + "\n")
+	case overNATS && m.flow.polledAt.IsZero():
+		content.WriteString(statusStyle.Render("Reading "+happenStream+" consumers…") + "\n")
+	case overNATS:
+		content.WriteString(fmt.Sprintf("%s %s\n",
+			statLabelStyle.Render(happenStream+" consumers"),
+			statusStyle.Render("polled "+formatAge(now.Sub(m.flow.polledAt))+" ago"),
+		))
+	}
+	if overNATS && !m.flow.polledAt.IsZero() && len(m.flow.consumers) == 0 && m.flow.err == nil {
+		content.WriteString(statusStyle.Render("No durable consumers on the stream") + "\n")
+	}
+
+	// Bars share one scale, at least up to the critical threshold
+	scale := float64(flowCriticalLag)
+	for _, c := range m.flow.consumers {
+		scale = max(scale, c.metrics.ConsumerLag)
+	}
+	barWidth := max(10, width-85)
+	for _, c := range m.flow.consumers {
+		style := onlineStyle
+		switch c.state {
+		case "unhealthy":
+			style = offlineStyle
+		case "degraded":
+			style = busyStyle
+		}
+		bar := int(c.metrics.ConsumerLag / scale * float64(barWidth))
+		content.WriteString(fmt.Sprintf("%-18s %s%s %6.0f lag  ack %3.0f%%  %6.1f/s  %3.0f redelivered  %s\n",
+			truncate(c.node, 18),
+			style.Render(strings.Repeat("█", bar)),
+			strings.Repeat(" ", barWidth-bar),
+			c.metrics.ConsumerLag,
+			c.metrics.AckRate*100,
+			c.metrics.ProcessingRate,
+			c.metrics.DeliveryFailures,
+			style.Render(c.state),
+		))
+	}
+
+	content.WriteString("\n")
+	if len(patterns) == 0 {
+		content.WriteString(onlineStyle.Render("No imbalance patterns detected"))
+		return content.String()
+	}
+	for _, p := range patterns {
+		style := statusStyle
+		switch p.severity {
+		case "critical":
+			style = offlineStyle
+		case "severe", "moderate":
+			style = busyStyle
+		}
+		source := ""
+		if p.reported {
+			source = statusStyle.Render(" (reported by node)")
+		}
+		content.WriteString(fmt.Sprintf("%s %-12s %3.0f%%  lag %-6.0f %s%s\n",
+			style.Render(fmt.Sprintf("%-8s", p.severity)),
+			p.kind,
+			p.confidence*100,
+			p.metrics.ConsumerLag,
+			truncate(strings.Join(p.affectedNodes, ", "), max(20, width-50)),
+			source,
+		))
+	}
+	return strings.TrimRight(content.String(), "\n")
+}
@@ -0,0 +1,223 @@
+package happen
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The fixture is shared with Happen/tests/subject-conformance.test.ts
+const fixturePath = "../../Happen/tests/fixtures/subject-conformance.json"
+
+type conformanceFixture struct {
+	SubjectPatterns map[string]string `json:"subjectPatterns"`
+	Build           []struct {
+		Fn       string   `json:"fn"`
+		Args     []string `json:"args"`
+		Expected string   `json:"expected"`
+	} `json:"build"`
+	ParseEventSubject []struct {
+		Subject  string            `json:"subject"`
+		Expected *EventSubjectInfo `json:"expected"`
+	} `json:"parseEventSubject"`
+	ParseStateSubject []struct {
+		Subject  string            `json:"subject"`
+		Expected *StateSubjectInfo `json:"expected"`
+	} `json:"parseStateSubject"`
+	IsSystemSubject        []subjectCase `json:"isSystemSubject"`
+	IsAdminSubject         []subjectCase `json:"isAdminSubject"`
+	IsValidSubject         []subjectCase `json:"isValidSubject"`
+	IsValidWildcardSubject []subjectCase `json:"isValidWildcardSubject"`
+	SubjectForEvent        []struct {
+		Type     string  `json:"type"`
+		Sender   string  `json:"sender"`
+		Target   *string `json:"target"`
+		Expected string  `json:"expected"`
+	} `json:"subjectForEvent"`
+	SubscriptionSubjects []struct {
+		NodeID   string   `json:"nodeId"`
+		Expected []string `json:"expected"`
+	} `json:"subscriptionSubjects"`
+	FilterSubjects []struct {
+		Subjects    []string `json:"subjects"`
+		AllowPrefix *string  `json:"allowPrefix"`
+		Expected    []string `json:"expected"`
+	} `json:"filterSubjects"`
+	MatchesSubjectPattern []struct {
+		Subject  string `json:"subject"`
+		Pattern  string `json:"pattern"`
+		Expected bool   `json:"expected"`
+	} `json:"matchesSubjectPattern"`
+	MatchesPattern []struct {
+		Pattern   string `json:"pattern"`
+		EventType string `json:"eventType"`
+		Separator string `json:"separator"`
+		Expected  bool   `json:"expected"`
+	} `json:"matchesPattern"`
+	CreateMatcher []struct {
+		Pattern   string `json:"pattern"`
+		EventType string `json:"eventType"`
+		Expected  bool   `json:"expected"`
+	} `json:"createMatcher"`
+}
+
+type subjectCase struct {
+	Subject  string `json:"subject"`
+	Expected bool   `json:"expected"`
+}
+
+func loadFixture(t *testing.T) conformanceFixture {
+	t.Helper()
+	data, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	var fx conformanceFixture
+	if err := json.Unmarshal(data, &fx); err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+	return fx
+}
+
+var builders = map[string]func(args []string) string{
+	"event":           func(a []string) string { return EventSubject(a[0]) },
+	"nodeEvent":       func(a []string) string { return NodeEventSubject(a[0], a[1]) },
+	"stateUpdate":     func(a []string) string { return StateUpdateSubject(a[0]) },
+	"stateSnapshot":   func(a []string) string { return StateSnapshotSubject(a[0]) },
+	"nodeStatus":      func(a []string) string { return NodeStatusSubject(a[0]) },
+	"request":         func(a []string) string { return RequestSubject(a[0]) },
+	"response":        func(a []string) string { return ResponseSubject(a[0]) },
+	"metrics":         func(a []string) string { return MetricsSubject(a[0]) },
+	"health":          func(a []string) string { return HealthSubject() },
+	"allEvents":       func(a []string) string { return AllEventsSubject() },
+	"allStateUpdates": func(a []string) string { return AllStateUpdatesSubject() },
+	"allNodeEvents": func(a []string) string {
+		if len(a) == 0 {
+			return AllNodeEventsSubject("")
+		}
+		return AllNodeEventsSubject(a[0])
+	},
+}
+
+func TestSubjectPatterns(t *testing.T) {
+	fx := loadFixture(t)
+	want := map[string]string{
+		"EVENTS":          Events,
+		"NODE_EVENTS":     NodeEvents,
+		"STATE":           State,
+		"STATE_UPDATES":   StateUpdates,
+		"STATE_SNAPSHOTS": StateSnapshots,
+		"SYSTEM":          System,
+		"NODE_STATUS":     NodeStatus,
+		"HEALTH":          Health,
+		"REQUESTS":        Requests,
+		"RESPONSES":       Responses,
+		"ADMIN":           Admin,
+		"METRICS":         Metrics,
+	}
+	if !reflect.DeepEqual(fx.SubjectPatterns, want) {
+		t.Errorf("SUBJECT_PATTERNS = %v, Go constants = %v", fx.SubjectPatterns, want)
+	}
+}
+
+func TestBuildSubjects(t *testing.T) {
+	for _, c := range loadFixture(t).Build {
+		build, ok := builders[c.Fn]
+		if !ok {
+			t.Errorf("no Go builder for %s", c.Fn)
+			continue
+		}
+		if got := build(c.Args); got != c.Expected {
+			t.Errorf("%s(%v) = %q, want %q", c.Fn, c.Args, got, c.Expected)
+		}
+	}
+}
+
+func TestParseSubjects(t *testing.T) {
+	fx := loadFixture(t)
+	for _, c := range fx.ParseEventSubject {
+		got, ok := ParseEventSubject(c.Subject)
+		if ok != (c.Expected != nil) || (ok && got != *c.Expected) {
+			t.Errorf("ParseEventSubject(%q) = %+v, %v; want %+v", c.Subject, got, ok, c.Expected)
+		}
+	}
+	for _, c := range fx.ParseStateSubject {
+		got, ok := ParseStateSubject(c.Subject)
+		if ok != (c.Expected != nil) || (ok && got != *c.Expected) {
+			t.Errorf("ParseStateSubject(%q) = %+v, %v; want %+v", c.Subject, got, ok, c.Expected)
+		}
+	}
+}
+
+func TestSubjectPredicates(t *testing.T) {
+	fx := loadFixture(t)
+	predicates := []struct {
+		name  string
+		fn    func(string) bool
+		cases []subjectCase
+	}{
+		{"IsSystemSubject", IsSystemSubject, fx.IsSystemSubject},
+		{"IsAdminSubject", IsAdminSubject, fx.IsAdminSubject},
+		{"IsValidSubject", IsValidSubject, fx.IsValidSubject},
+		{"IsValidWildcardSubject", IsValidWildcardSubject, fx.IsValidWildcardSubject},
+	}
+	for _, p := range predicates {
+		for _, c := range p.cases {
+			if got := p.fn(c.Subject); got != c.Expected {
+				t.Errorf("%s(%q) = %v, want %v", p.name, c.Subject, got, c.Expected)
+			}
+		}
+	}
+}
+
+func TestRouting(t *testing.T) {
+	fx := loadFixture(t)
+	for _, c := range fx.SubjectForEvent {
+		target := ""
+		if c.Target != nil {
+			target = *c.Target
+		}
+		if got := SubjectForEvent(c.Type, c.Sender, target); got != c.Expected {
+			t.Errorf("SubjectForEvent(%q, %q, %q) = %q, want %q", c.Type, c.Sender, target, got, c.Expected)
+		}
+	}
+	for _, c := range fx.SubscriptionSubjects {
+		if got := SubscriptionSubjects(c.NodeID); !reflect.DeepEqual(got, c.Expected) {
+			t.Errorf("SubscriptionSubjects(%q) = %v, want %v", c.NodeID, got, c.Expected)
+		}
+	}
+	for _, c := range fx.FilterSubjects {
+		var filter func(string) bool
+		if c.AllowPrefix != nil {
+			prefix := *c.AllowPrefix
+			filter = func(subject string) bool { return strings.HasPrefix(subject, prefix) }
+		}
+		if got := FilterSubjects(c.Subjects, filter); !reflect.DeepEqual(got, c.Expected) {
+			t.Errorf("FilterSubjects(%v) = %v, want %v", c.Subjects, got, c.Expected)
+		}
+	}
+}
+
+func TestMatchesSubjectPattern(t *testing.T) {
+	for _, c := range loadFixture(t).MatchesSubjectPattern {
+		if got := MatchesSubjectPattern(c.Subject, c.Pattern); got != c.Expected {
+			t.Errorf("MatchesSubjectPattern(%q, %q) = %v, want %v", c.Subject, c.Pattern, got, c.Expected)
+		}
+	}
+}
+
+func TestEventPatterns(t *testing.T) {
+	fx := loadFixture(t)
+	for _, c := range fx.MatchesPattern {
+		if got := MatchesPattern(c.Pattern, c.EventType, c.Separator); got != c.Expected {
+			t.Errorf("MatchesPattern(%q, %q, %q) = %v, want %v", c.Pattern, c.EventType, c.Separator, got, c.Expected)
+		}
+	}
+	for _, c := range fx.CreateMatcher {
+		if got := NewMatcher(c.Pattern)(c.EventType); got != c.Expected {
+			t.Errorf("NewMatcher(%q)(%q) = %v, want %v", c.Pattern, c.EventType, got, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package happen
+
+import (
+	"regexp"
+	"strings"
+)
+
+// reSpecial is the set JavaScript code escapes with /[.*+?^${}()|[\]\\]/g
+var reSpecial = regexp.MustCompile(`[.*+?^${}()|\[\]\\]`)
+
+func escapeJS(s string) string {
+	return reSpecial.ReplaceAllString(s, `\$0`)
+}
+
+// Matcher reports whether an event type matches a pattern
+type Matcher func(eventType string) bool
+
+// NewMatcher mirrors createMatcher in patterns/index.ts, the matcher behind node.on():
+//   - a pattern containing * is a regular expression with dots escaped and * as .*, so it
+//     spans dots ("order.*" matches "order.item.added")
+//   - otherwise a leading {a,b} group followed by a suffix matches each alternative + suffix
+//   - anything else is an exact match
+func NewMatcher(pattern string) Matcher {
+	if strings.Contains(pattern, "*") {
+		expr := strings.ReplaceAll(pattern, ".", `\.`)
+		expr = strings.ReplaceAll(expr, "*", ".*")
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			// new RegExp throws in TypeScript; here the pattern simply never matches
+			return func(string) bool { return false }
+		}
+		return re.MatchString
+	}
+	if strings.Contains(pattern, "{") && strings.Contains(pattern, "}") {
+		if strings.HasPrefix(pattern, "{") {
+			if end := strings.Index(pattern, "}"); end > 1 {
+				alternatives := strings.Split(pattern[1:end], ",")
+				suffix := pattern[end+1:]
+				return func(eventType string) bool {
+					for _, alt := range alternatives {
+						if eventType == alt+suffix {
+							return true
+						}
+					}
+					return false
+				}
+			}
+		}
+	}
+	return func(eventType string) bool { return eventType == pattern }
+}
+
+var (
+	alternativesRe = regexp.MustCompile(`\{([^}]+)\}`)
+	// The second escaping pass in pattern-matcher.ts; note it lacks *, { and } and \
+	secondPassRe = regexp.MustCompile(`(\.|\[|\]|\(|\)|\^|\$|\+|\?|\.|\|)`)
+)
+
+// patternRegexp is convertPatternToRegExp. Its second pass escapes the group that the first
+// pass builds for {a,b}, so alternatives only ever match their own literal text; that is
+// kept for parity.
+func patternRegexp(pattern, separator string) (*regexp.Regexp, error) {
+	expr := alternativesRe.ReplaceAllStringFunc(pattern, func(group string) string {
+		var parts []string
+		for _, part := range strings.Split(group[1:len(group)-1], ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, escapeJS(part))
+			}
+		}
+		return "(?:" + strings.Join(parts, "|") + ")"
+	})
+	expr = secondPassRe.ReplaceAllString(expr, `\$1`)
+	expr = strings.ReplaceAll(expr, "*", "([^"+escapeJS(separator)+"]+)")
+	return regexp.Compile("^" + expr + "$")
+}
+
+// MatchesPattern mirrors matchesPattern in core/pattern-matcher.ts, used for lifecycle hook
+// filters: * matches one or more characters other than separator (default "-") and {a,b}
+// lists alternatives. Invalid patterns never match.
+func MatchesPattern(pattern, eventType, separator string) bool {
+	if separator == "" {
+		separator = "-"
+	}
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	if !strings.ContainsAny(pattern, "{}*") {
+		return false
+	}
+	re, err := patternRegexp(pattern, separator)
+	return err == nil && re.MatchString(eventType)
+}
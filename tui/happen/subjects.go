@@ -0,0 +1,160 @@
+// Package happen ports the subject routing in Happen's transport/subjects.ts and the event
+// type patterns in patterns/index.ts and core/pattern-matcher.ts, so Go code builds, parses
+// and matches subjects exactly as Happen nodes do. Behaviour is pinned by the fixtures in
+// Happen/tests/fixtures/subject-conformance.json, which the TypeScript tests also run.
+package happen
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Subject prefixes (SUBJECT_PATTERNS)
+const (
+	Events         = "happen.events"
+	NodeEvents     = "happen.events.node"
+	State          = "happen.state"
+	StateUpdates   = "happen.state.updates"
+	StateSnapshots = "happen.state.snapshots"
+	System         = "happen.system"
+	NodeStatus     = "happen.system.node.status"
+	Health         = "happen.system.health"
+	Requests       = "happen.req"
+	Responses      = "happen.resp"
+	Admin          = "happen.admin"
+	Metrics        = "happen.admin.metrics"
+)
+
+// SubjectBuilder
+
+func EventSubject(eventType string) string { return Events + "." + eventType }
+func NodeEventSubject(nodeID, eventType string) string {
+	return NodeEvents + "." + nodeID + "." + eventType
+}
+func StateUpdateSubject(nodeID string) string   { return StateUpdates + "." + nodeID }
+func StateSnapshotSubject(nodeID string) string { return StateSnapshots + "." + nodeID }
+func NodeStatusSubject(nodeID string) string    { return NodeStatus + "." + nodeID }
+func RequestSubject(requestType string) string  { return Requests + "." + requestType }
+func ResponseSubject(requestID string) string   { return Responses + "." + requestID }
+func MetricsSubject(nodeID string) string       { return Metrics + "." + nodeID }
+func HealthSubject() string                     { return Health }
+func AllEventsSubject() string                  { return Events + ".*" }
+func AllStateUpdatesSubject() string            { return StateUpdates + ".*" }
+
+// AllNodeEventsSubject covers one node's events, or every node's when nodeID is empty
+func AllNodeEventsSubject(nodeID string) string {
+	if nodeID != "" {
+		return NodeEvents + "." + nodeID + ".*"
+	}
+	return NodeEvents + ".*"
+}
+
+// SubjectParser
+
+// EventSubjectInfo is what ParseEventSubject extracts
+type EventSubjectInfo struct {
+	Pattern   string `json:"pattern"`
+	EventType string `json:"eventType,omitempty"`
+	NodeID    string `json:"nodeId,omitempty"`
+}
+
+// StateSubjectInfo is what ParseStateSubject extracts
+type StateSubjectInfo struct {
+	Pattern   string `json:"pattern"`
+	Operation string `json:"operation,omitempty"` // update or snapshot
+	NodeID    string `json:"nodeId,omitempty"`
+}
+
+var (
+	eventSubjectRe     = regexp.MustCompile(`^happen\.events\.(.+)$`)
+	nodeEventSubjectRe = regexp.MustCompile(`^happen\.events\.node\.([^.]+)\.(.+)$`)
+	stateUpdateRe      = regexp.MustCompile(`^happen\.state\.updates\.([^.]+)$`)
+	stateSnapshotRe    = regexp.MustCompile(`^happen\.state\.snapshots\.([^.]+)$`)
+)
+
+// ParseEventSubject splits an event subject. As in subjects.ts the broadcast form is tried
+// first and also matches node subjects, so happen.events.node.n1.x yields event type node.n1.x.
+func ParseEventSubject(subject string) (EventSubjectInfo, bool) {
+	if m := eventSubjectRe.FindStringSubmatch(subject); m != nil {
+		return EventSubjectInfo{Pattern: Events, EventType: m[1]}, true
+	}
+	if m := nodeEventSubjectRe.FindStringSubmatch(subject); m != nil {
+		return EventSubjectInfo{Pattern: NodeEvents, NodeID: m[1], EventType: m[2]}, true
+	}
+	return EventSubjectInfo{}, false
+}
+
+func ParseStateSubject(subject string) (StateSubjectInfo, bool) {
+	if m := stateUpdateRe.FindStringSubmatch(subject); m != nil {
+		return StateSubjectInfo{Pattern: StateUpdates, Operation: "update", NodeID: m[1]}, true
+	}
+	if m := stateSnapshotRe.FindStringSubmatch(subject); m != nil {
+		return StateSubjectInfo{Pattern: StateSnapshots, Operation: "snapshot", NodeID: m[1]}, true
+	}
+	return StateSubjectInfo{}, false
+}
+
+// IsSystemSubject is a plain prefix test, so happen.systemx counts too
+func IsSystemSubject(subject string) bool { return strings.HasPrefix(subject, System) }
+
+func IsAdminSubject(subject string) bool { return strings.HasPrefix(subject, Admin) }
+
+// EventRouter
+
+// SubjectForEvent picks where an event of the given type from sender is published;
+// targetNodeID is empty for a broadcast
+func SubjectForEvent(eventType, sender, targetNodeID string) string {
+	if strings.HasPrefix(eventType, "system.") {
+		if eventType == "system.node.status" {
+			return NodeStatusSubject(sender)
+		}
+		return System + "." + eventType[len("system."):]
+	}
+	if targetNodeID != "" {
+		return NodeEventSubject(targetNodeID, eventType)
+	}
+	return EventSubject(eventType)
+}
+
+// SubscriptionSubjects lists what a node subscribes to
+func SubscriptionSubjects(nodeID string) []string {
+	return []string{
+		AllEventsSubject(),
+		AllNodeEventsSubject(nodeID),
+		System + ".*",
+		HealthSubject(),
+	}
+}
+
+// FilterSubjects keeps system subjects and whatever filter accepts; a nil filter keeps all
+func FilterSubjects(subjects []string, filter func(string) bool) []string {
+	kept := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		if IsSystemSubject(subject) || filter == nil || filter(subject) {
+			kept = append(kept, subject)
+		}
+	}
+	return kept
+}
+
+// SubjectValidator
+
+var (
+	validSubjectRe         = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	validWildcardSubjectRe = regexp.MustCompile(`^[a-zA-Z0-9._*>-]+$`)
+)
+
+func IsValidSubject(subject string) bool { return validSubjectRe.MatchString(subject) }
+
+func IsValidWildcardSubject(subject string) bool { return validWildcardSubjectRe.MatchString(subject) }
+
+// MatchesSubjectPattern applies NATS-style wildcards the way subjects.ts does: * is one or
+// more non-dot characters and > is anything, including nothing. Other characters are not
+// escaped, so a pattern the regular expression engine rejects never matches.
+func MatchesSubjectPattern(subject, pattern string) bool {
+	expr := strings.ReplaceAll(pattern, ".", `\.`)
+	expr = strings.ReplaceAll(expr, "*", `[^.]+`)
+	expr = strings.ReplaceAll(expr, ">", `.*`)
+	re, err := regexp.Compile("^" + expr + "$")
+	return err == nil && re.MatchString(subject)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Liveness states derived from heartbeat age
+const (
+	livenessOnline  = "online"
+	livenessStale   = "stale"
+	livenessOffline = "offline"
+)
+
+// Registered agents heartbeat every 10s. By default an agent is stale after 30s without one,
+// the registry's own timeout, and offline after 60s.
+const (
+	defaultStaleTTL   = 30 * time.Second
+	defaultOfflineTTL = 60 * time.Second
+)
+
+// heartbeatTracker records the last heartbeat per agent and derives liveness from configurable TTLs
+type heartbeatTracker struct {
+	staleTTL   time.Duration
+	offlineTTL time.Duration
+	lastSeen   map[string]time.Time
+	liveness   map[string]string
+}
+
+func newHeartbeatTracker(staleTTL, offlineTTL time.Duration) *heartbeatTracker {
+	return &heartbeatTracker{
+		staleTTL:   staleTTL,
+		offlineTTL: offlineTTL,
+		lastSeen:   make(map[string]time.Time),
+		liveness:   make(map[string]string),
+	}
+}
+
+func (t *heartbeatTracker) beat(agentID string, at time.Time) {
+	if at.After(t.lastSeen[agentID]) {
+		t.lastSeen[agentID] = at
+	}
+}
+
+// track starts newly registered agents online. Their liveness is only derived from heartbeat
+// age once they have sent one, so agents on a bridge without heartbeats never go stale.
+func (t *heartbeatTracker) track(agents []AgentInfo) {
+	for _, agent := range agents {
+		if _, ok := t.liveness[agent.ID]; !ok {
+			t.liveness[agent.ID] = livenessOnline
+		}
+	}
+}
+
+func (t *heartbeatTracker) derive(agentID string, now time.Time) string {
+	seen, ok := t.lastSeen[agentID]
+	if !ok {
+		return livenessOnline
+	}
+	switch age := now.Sub(seen); {
+	case age >= t.offlineTTL:
+		return livenessOffline
+	case age >= t.staleTTL:
+		return livenessStale
+	}
+	return livenessOnline
+}
+
+// check re-derives liveness for every agent and returns events for each transition
+func (t *heartbeatTracker) check(agents []AgentInfo, now time.Time) []EventInfo {
+	var events []EventInfo
+	for _, agent := range agents {
+		previous := t.liveness[agent.ID]
+		current := t.derive(agent.ID, now)
+		if previous == current || previous == "" {
+			t.liveness[agent.ID] = current
+			continue
+		}
+		t.liveness[agent.ID] = current
+
+		message := fmt.Sprintf("heartbeat resumed after %s", formatAge(now.Sub(t.lastSeen[agent.ID])))
+		if current != livenessOnline {
+			message = fmt.Sprintf("no heartbeat for %s", formatAge(now.Sub(t.lastSeen[agent.ID])))
+		}
+		events = append(events, EventInfo{
+			Timestamp: now.Format("15:04:05"),
+			At:        now,
+			Type:      "agent:" + current,
+			From:      agent.Name,
+			To:        "control-center",
+			Message:   message,
+		})
+	}
+	return events
+}
+
+// effectiveStatus combines the reported status with heartbeat liveness
+func (t *heartbeatTracker) effectiveStatus(agent AgentInfo, now time.Time) string {
+	if agent.Status == "offline" {
+		return agent.Status
+	}
+	if liveness := t.derive(agent.ID, now); liveness != livenessOnline {
+		return liveness
+	}
+	return agent.Status
+}
+
+func (t *heartbeatTracker) lastSeenText(agentID string, now time.Time) string {
+	seen, ok := t.lastSeen[agentID]
+	if !ok {
+		return "-"
+	}
+	return formatAge(now.Sub(seen)) + " ago"
+}
+
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+}
+
+// liveAgents returns the agent list with heartbeat-derived statuses applied
+func (m *controlCenterModel) liveAgents(now time.Time) []AgentInfo {
+	agents := make([]AgentInfo, len(m.agents))
+	for i, agent := range m.agents {
+		agent.Status = m.heartbeats.effectiveStatus(agent, now)
+		agents[i] = agent
+	}
+	return agents
+}
+
+func (m *controlCenterModel) checkHeartbeats(now time.Time) {
+	for _, event := range m.heartbeats.check(m.agents, now) {
+		m.addEvent(event)
+	}
+}
+
+// AgentHeartbeatUpdate is forwarded from the registry's agent:heartbeat event
+type AgentHeartbeatUpdate struct {
+	AgentID string
+	At      time.Time
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Analytics sub-views
+const (
+	analyticsTrends = iota
+	analyticsHeatmap
+)
+
+var heatShades = []rune(" ░▒▓█")
+
+var heatStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+
+// eventFilter narrows the Events tab to one agent and time window
+type eventFilter struct {
+	agent string
+	from  time.Time
+	to    time.Time
+}
+
+func (f *eventFilter) matches(event EventInfo) bool {
+	if f.agent != "" && event.From != f.agent && event.To != f.agent {
+		return false
+	}
+	return !event.At.Before(f.from) && event.At.Before(f.to)
+}
+
+// activityGrid counts events per agent (rows) and time bucket (columns)
+type activityGrid struct {
+	agents  []string
+	start   time.Time
+	bucket  time.Duration
+	counts  [][]int
+	peak    int
+	columns int
+}
+
+func buildActivityGrid(agents []AgentInfo, events []EventInfo, now time.Time, window time.Duration, columns int) activityGrid {
+	grid := activityGrid{
+		start:   now.Add(-window),
+		bucket:  window / time.Duration(columns),
+		columns: columns,
+	}
+
+	rows := map[string]int{}
+	addRow := func(name string) {
+		if _, ok := rows[name]; !ok && !nonPeerEndpoints[name] {
+			rows[name] = len(grid.agents)
+			grid.agents = append(grid.agents, name)
+		}
+	}
+	for _, agent := range agents {
+		addRow(agent.Name)
+	}
+	for _, event := range events {
+		addRow(event.From)
+		addRow(event.To)
+	}
+	sort.Strings(grid.agents)
+	for i, name := range grid.agents {
+		rows[name] = i
+	}
+
+	grid.counts = make([][]int, len(grid.agents))
+	for i := range grid.counts {
+		grid.counts[i] = make([]int, columns)
+	}
+	for _, event := range events {
+		if event.At.Before(grid.start) || event.At.After(now) {
+			continue
+		}
+		col := min(int(event.At.Sub(grid.start)/grid.bucket), columns-1)
+		for _, name := range []string{event.From, event.To} {
+			if row, ok := rows[name]; ok {
+				grid.counts[row][col]++
+				grid.peak = max(grid.peak, grid.counts[row][col])
+			}
+		}
+	}
+	return grid
+}
+
+func (g activityGrid) cellWindow(col int) (time.Time, time.Time) {
+	from := g.start.Add(time.Duration(col) * g.bucket)
+	return from, from.Add(g.bucket)
+}
+
+func (g activityGrid) shade(count int) rune {
+	if count == 0 || g.peak == 0 {
+		return '·'
+	}
+	idx := 1 + (count-1)*(len(heatShades)-2)/max(1, g.peak-1)
+	return heatShades[min(idx, len(heatShades)-1)]
+}
+
+func (m *controlCenterModel) activityGrid(width int) activityGrid {
+	window := chartWindows[m.chartWindow]
+	columns := max(10, min(120, width-22))
+	return buildActivityGrid(m.agents, m.eventLog.events, time.Now(), window.duration, columns)
+}
+
+func (m *controlCenterModel) renderHeatmap(width int) string {
+	grid := m.activityGrid(width)
+	window := chartWindows[m.chartWindow]
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s  %s %s  %s %s\n\n",
+		statLabelStyle.Render("Activity heatmap"),
+		statusStyle.Render("window:"), statValueStyle.Render(window.name),
+		statusStyle.Render("bucket:"), statValueStyle.Render(formatDuration(grid.bucket)),
+	))
+	if len(grid.agents) == 0 {
+		return content.String() + statusStyle.Render("No agent activity recorded yet")
+	}
+
+	m.heatmapRow = min(m.heatmapRow, len(grid.agents)-1)
+	m.heatmapCol = min(m.heatmapCol, grid.columns-1)
+	for row, name := range grid.agents {
+		content.WriteString(fmt.Sprintf("%-18s ", truncate(name, 18)))
+		for col, count := range grid.counts[row] {
+			cell := string(grid.shade(count))
+			if row == m.heatmapRow && col == m.heatmapCol {
+				content.WriteString(heatStyle.Reverse(true).Render(cell))
+			} else {
+				content.WriteString(heatStyle.Render(cell))
+			}
+		}
+		content.WriteString("\n")
+	}
+
+	axis := fmt.Sprintf("%-18s %s", "", grid.start.Format("15:04"))
+	end := "now"
+	content.WriteString(statusStyle.Render(axis+strings.Repeat(" ", max(1, grid.columns-len(grid.start.Format("15:04"))-len(end)))+end) + "\n\n")
+
+	from, to := grid.cellWindow(m.heatmapCol)
+	content.WriteString(fmt.Sprintf("%s %s %s–%s: %d events (peak %d)\n",
+		statLabelStyle.Render("Selected:"),
+		grid.agents[m.heatmapRow],
+		from.Format("15:04:05"),
+		to.Format("15:04:05"),
+		grid.counts[m.heatmapRow][m.heatmapCol],
+		grid.peak,
+	))
+	content.WriteString(statusStyle.Render("←↑↓→: move • Enter: show in Events • w: window • h: trends"))
+	return content.String()
+}
+
+func (m *controlCenterModel) handleHeatmapKey(key string) {
+	switch key {
+	case "up", "k":
+		m.heatmapRow = max(0, m.heatmapRow-1)
+	case "down", "j":
+		m.heatmapRow++
+	case "left":
+		m.heatmapCol = max(0, m.heatmapCol-1)
+	case "right", "l":
+		m.heatmapCol++
+	case "enter":
+		grid := m.activityGrid(m.analyticsView.Width - 4)
+		if len(grid.agents) == 0 {
+			return
+		}
+		row := min(m.heatmapRow, len(grid.agents)-1)
+		from, to := grid.cellWindow(min(m.heatmapCol, grid.columns-1))
+		m.eventFilter = &eventFilter{agent: grid.agents[row], from: from, to: to}
+		m.activeTab = tabEvents
+		m.updateEventView()
+		m.eventView.GotoTop()
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// History is kept for the longest selectable chart window, and capped in length so a bridge
+// pushing updates rapidly cannot grow it without bound
+const (
+	historyRetention  = 24 * time.Hour
+	maxHistorySamples = 20000
+)
+
+var chartWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"24h", 24 * time.Hour},
+}
+
+type statsSample struct {
+	at    time.Time
+	stats SystemStats
+}
+
+type agentSample struct {
+	at    time.Time
+	agent AgentInfo
+}
+
+// metricsHistory keeps rolling snapshots of system and per-agent metrics
+type metricsHistory struct {
+	system []statsSample
+	agents map[string][]agentSample
+}
+
+func newMetricsHistory() *metricsHistory {
+	return &metricsHistory{agents: make(map[string][]agentSample)}
+}
+
+func (h *metricsHistory) recordStats(at time.Time, stats SystemStats) {
+	h.system = append(h.system, statsSample{at: at, stats: stats})
+	h.system = trimSamples(h.system, at, func(s statsSample) time.Time { return s.at })
+}
+
+func (h *metricsHistory) recordAgents(at time.Time, agents []AgentInfo) {
+	for _, agent := range agents {
+		samples := append(h.agents[agent.ID], agentSample{at: at, agent: agent})
+		h.agents[agent.ID] = trimSamples(samples, at, func(s agentSample) time.Time { return s.at })
+	}
+	// Agents that stopped reporting age out with their samples
+	for id, samples := range h.agents {
+		if samples = trimSamples(samples, at, func(s agentSample) time.Time { return s.at }); len(samples) == 0 {
+			delete(h.agents, id)
+		}
+	}
+}
+
+func trimSamples[T any](samples []T, now time.Time, at func(T) time.Time) []T {
+	cutoff := now.Add(-historyRetention)
+	drop := 0
+	for drop < len(samples) && at(samples[drop]).Before(cutoff) {
+		drop++
+	}
+	drop = max(drop, len(samples)-maxHistorySamples)
+	return samples[drop:]
+}
+
+// metricSeries names a plottable value and how to extract it from a sample
+type metricSeries struct {
+	name   string
+	format string
+	// rate series plot the per-minute change of a counter instead of its value
+	rate  bool
+	value func(interface{}) float64
+}
+
+var systemSeries = []metricSeries{
+	{name: "Online Agents", format: "%.0f", value: func(s interface{}) float64 { return float64(s.(SystemStats).OnlineAgents) }},
+	{name: "Tasks/min", format: "%.1f", rate: true, value: func(s interface{}) float64 { return float64(s.(SystemStats).TasksCompleted) }},
+	{name: "Avg Response", format: "%.0fms", value: func(s interface{}) float64 { return s.(SystemStats).AvgResponseTime }},
+	{name: "Success Rate", format: "%.0f%%", value: func(s interface{}) float64 { return s.(SystemStats).SuccessRate * 100 }},
+	{name: "Events/min", format: "%.0f", value: func(s interface{}) float64 { return float64(s.(SystemStats).EventsPerMinute) }},
+}
+
+var agentSeries = []metricSeries{
+	{name: "Tasks/min", format: "%.1f", rate: true, value: func(a interface{}) float64 { return float64(a.(AgentInfo).Tasks) }},
+	{name: "Response Time", format: "%.0fms", value: func(a interface{}) float64 { return a.(AgentInfo).ResponseTime }},
+	{name: "Success Rate", format: "%.0f%%", value: func(a interface{}) float64 { return a.(AgentInfo).SuccessRate * 100 }},
+}
+
+// bucketSeries averages samples into n equal buckets covering [now-window, now].
+// Empty buckets carry the previous value forward so lines stay continuous.
+func bucketSeries(times []time.Time, values []float64, now time.Time, window time.Duration, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	out := make([]float64, n)
+	counts := make([]int, n)
+	start := now.Add(-window)
+	for i, at := range times {
+		if at.Before(start) || at.After(now) || math.IsNaN(values[i]) {
+			continue
+		}
+		b := int(float64(at.Sub(start)) / float64(window) * float64(n))
+		b = min(b, n-1)
+		out[b] += values[i]
+		counts[b]++
+	}
+
+	last := math.NaN()
+	for b := range out {
+		if counts[b] > 0 {
+			out[b] /= float64(counts[b])
+			last = out[b]
+		} else {
+			out[b] = last
+		}
+	}
+	return out
+}
+
+// extract turns timestamped samples into a value series, converting counters to per-minute rates
+func extract(times []time.Time, samples []interface{}, series metricSeries) []float64 {
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		v := series.value(sample)
+		if !series.rate {
+			values[i] = v
+			continue
+		}
+		if i == 0 {
+			values[i] = math.NaN()
+			continue
+		}
+		dt := times[i].Sub(times[i-1]).Minutes()
+		delta := v - series.value(samples[i-1])
+		if dt <= 0 || delta < 0 {
+			values[i] = math.NaN()
+		} else {
+			values[i] = delta / dt
+		}
+	}
+	return values
+}
+
+func (h *metricsHistory) systemSeriesValues(series metricSeries, now time.Time, window time.Duration, n int) []float64 {
+	times := make([]time.Time, len(h.system))
+	samples := make([]interface{}, len(h.system))
+	for i, s := range h.system {
+		times[i] = s.at
+		samples[i] = s.stats
+	}
+	return bucketSeries(times, extract(times, samples, series), now, window, n)
+}
+
+func (h *metricsHistory) agentSeriesValues(agentID string, series metricSeries, now time.Time, window time.Duration, n int) []float64 {
+	history := h.agents[agentID]
+	times := make([]time.Time, len(history))
+	samples := make([]interface{}, len(history))
+	for i, s := range history {
+		times[i] = s.at
+		samples[i] = s.agent
+	}
+	return bucketSeries(times, extract(times, samples, series), now, window, n)
+}
+
+// renderTrends draws a sparkline per series plus a braille chart of the selected series
+func (m *controlCenterModel) renderTrends(width int) string {
+	now := m.clock()
+	window := chartWindows[m.chartWindow]
+
+	series := systemSeries
+	values := func(s metricSeries, n int) []float64 {
+		return m.history.systemSeriesValues(s, now, window.duration, n)
+	}
+	scope := "System"
+	if m.analyticsAgent != "" {
+		series = agentSeries
+		values = func(s metricSeries, n int) []float64 {
+			return m.history.agentSeriesValues(m.analyticsAgent, s, now, window.duration, n)
+		}
+		scope = "Agent " + m.agentName(m.analyticsAgent)
+	}
+	if m.replay != nil {
+		scope += busyStyle.Render(" ⏺ replay " + now.Format("2006-01-02 15:04:05"))
+	}
+	selected := series[m.chartMetric%len(series)]
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s  %s  %s\n\n",
+		statLabelStyle.Render(scope),
+		statusStyle.Render("window:"),
+		statValueStyle.Render(window.name),
+	))
+
+	sparkWidth := max(10, width-36)
+	for i, s := range series {
+		points := values(s, sparkWidth)
+		current := "-"
+		if v, ok := lastValue(points); ok {
+			current = fmt.Sprintf(s.format, v)
+		}
+		marker := "  "
+		if i == m.chartMetric%len(series) {
+			marker = "▸ "
+		}
+		content.WriteString(fmt.Sprintf("%s%-16s %s %s\n",
+			marker,
+			statLabelStyle.Render(s.name),
+			statValueStyle.Render(sparkline(points)),
+			current,
+		))
+	}
+
+	content.WriteString("\n" + statLabelStyle.Render(selected.name) + "\n")
+	content.WriteString(brailleChart(values(selected, width*2), width, 8, selected.format))
+	content.WriteString("\n" + statusStyle.Render("w: window • m: metric • a: system series • h: heatmap • Agents tab Enter: chart agent"))
+	return content.String()
+}
+
+func (m *controlCenterModel) agentName(id string) string {
+	for _, agent := range m.agents {
+		if agent.ID == id {
+			return agent.Name
+		}
+	}
+	return id
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Integrity badges
+const (
+	integrityVerified   = "verified"
+	integrityUnverified = "unverified"
+	integrityTampered   = "tampered"
+)
+
+// Events tab integrity filters
+const (
+	integrityShowAll = iota
+	integrityShowProblems
+	integrityFilterCount
+)
+
+// canonicalJSON reproduces Happen's utils/canonicalStringify: JSON.stringify with object keys
+// sorted. JavaScript still emits integer-like keys first in numeric order, then the rest in
+// UTF-16 order, and formats numbers and escapes strings its own way, so all three are mirrored.
+func canonicalJSON(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case float64:
+		buf.WriteString(jsNumber(v))
+	case string:
+		writeJSString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		buf.WriteByte('{')
+		for i, key := range jsKeyOrder(v) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("cannot canonicalise %T", v)
+	}
+	return nil
+}
+
+// jsKeyOrder is the order JSON.stringify emits an object built from sorted keys
+func jsKeyOrder(obj map[string]interface{}) []string {
+	var indexes, names []string
+	for key := range obj {
+		if isArrayIndex(key) {
+			indexes = append(indexes, key)
+		} else {
+			names = append(names, key)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		a, _ := strconv.ParseUint(indexes[i], 10, 32)
+		b, _ := strconv.ParseUint(indexes[j], 10, 32)
+		return a < b
+	})
+	sort.Slice(names, func(i, j int) bool { return lessUTF16(names[i], names[j]) })
+	return append(indexes, names...)
+}
+
+// isArrayIndex matches the canonical integer keys JavaScript enumerates first
+func isArrayIndex(key string) bool {
+	n, err := strconv.ParseUint(key, 10, 32)
+	return err == nil && n < math.MaxUint32 && strconv.FormatUint(n, 10) == key
+}
+
+// lessUTF16 compares like Array.prototype.sort on strings, by UTF-16 code units
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+// jsNumber formats like ECMAScript Number::toString
+func jsNumber(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "null"
+	}
+	if f == 0 {
+		return "0"
+	}
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+	// Shortest round-trip digits and exponent, as d.ddde±x
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+	k, n := len(digits), e+1
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits
+	}
+	s := digits[:1]
+	if k > 1 {
+		s += "." + digits[1:]
+	}
+	if n-1 >= 0 {
+		return sign + s + "e+" + strconv.Itoa(n-1)
+	}
+	return sign + s + "e-" + strconv.Itoa(1-n)
+}
+
+// writeJSString escapes like JSON.stringify, which leaves <, >, & and U+2028/2029 alone
+func writeJSString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// LoadTrustedKeys reads PEM public keys (PKIX "PUBLIC KEY" or PKCS#1 "RSA PUBLIC KEY")
+func LoadTrustedKeys(path string) ([]crypto.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		key, err := parsePublicKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no PEM public keys found", path)
+	}
+	return keys, nil
+}
+
+func parsePublicKey(block *pem.Block) (crypto.PublicKey, error) {
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// integrityVerifier checks EventIntegrity blocks against the trusted keys
+type integrityVerifier struct {
+	keys   []crypto.PublicKey
+	strict bool // show only verified events; the rest are kept and reappear when strict is off
+}
+
+// findIntegrity locates the Happen event carrying context.integrity in a bridge payload
+func findIntegrity(payload interface{}) (event map[string]interface{}, integrity map[string]interface{}) {
+	queue := []interface{}{payload}
+	for len(queue) > 0 {
+		node, ok := queue[0].(map[string]interface{})
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		if context, ok := node["context"].(map[string]interface{}); ok {
+			if integrity, ok := context["integrity"].(map[string]interface{}); ok {
+				return node, integrity
+			}
+		}
+		queue = append(queue, node["data"], node["event"], node["payload"])
+	}
+	return nil, nil
+}
+
+// eventHash is calculateEventHash: SHA-256 over the canonical event with context.integrity removed
+func eventHash(event map[string]interface{}) (string, error) {
+	context := make(map[string]interface{})
+	for k, v := range event["context"].(map[string]interface{}) {
+		if k != "integrity" {
+			context[k] = v
+		}
+	}
+	copied := make(map[string]interface{}, len(event))
+	for k, v := range event {
+		copied[k] = v
+	}
+	copied["context"] = context
+
+	canonical, err := canonicalJSON(copied)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// check returns the badge for a raw event payload and a short reason
+func (v *integrityVerifier) check(payload interface{}) (status, note string) {
+	event, integrity := findIntegrity(payload)
+	if event == nil {
+		return integrityUnverified, "no integrity"
+	}
+	claimed, _ := integrity["hash"].(string)
+	hash, err := eventHash(event)
+	if err != nil {
+		return integrityUnverified, err.Error()
+	}
+	if hash != claimed {
+		return integrityTampered, "hash mismatch"
+	}
+
+	signature, _ := integrity["signature"].(string)
+	publicKey, _ := integrity["publicKey"].(string)
+	switch {
+	case signature == "":
+		return integrityUnverified, "hash ok, unsigned"
+	case strings.HasPrefix(signature, "sim-"):
+		return integrityUnverified, "simulated signature"
+	case publicKey == "":
+		return integrityUnverified, "no public key"
+	}
+
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return integrityUnverified, "unreadable public key"
+	}
+	key, err := parsePublicKey(block)
+	if err != nil {
+		return integrityUnverified, "unreadable public key"
+	}
+	trusted := v.trusted(key)
+	if trusted == nil {
+		return integrityUnverified, "untrusted key"
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !verifySignature(trusted, []byte(hash), sig) {
+		return integrityTampered, "bad signature"
+	}
+	return integrityVerified, "signed by trusted key"
+}
+
+func (v *integrityVerifier) trusted(key crypto.PublicKey) crypto.PublicKey {
+	for _, k := range v.keys {
+		if eq, ok := k.(interface{ Equal(crypto.PublicKey) bool }); ok && eq.Equal(key) {
+			return k
+		}
+	}
+	return nil
+}
+
+// verifySignature matches the identity provider: Ed25519 signs the hash string directly,
+// RSA and ECDSA sign its SHA-256 digest
+func verifySignature(key crypto.PublicKey, data, sig []byte) bool {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, data, sig)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	}
+	return false
+}
+
+func integrityBadge(status string) string {
+	switch status {
+	case integrityVerified:
+		return onlineStyle.Render("✔")
+	case integrityTampered:
+		return offlineStyle.Render("✗")
+	}
+	return statusStyle.Render("?")
+}
+
+// showIntegrity applies strict mode and the Events tab integrity filter
+func (m *controlCenterModel) showIntegrity(event EventInfo) bool {
+	if m.integrity.strict && event.Integrity != integrityVerified {
+		return false
+	}
+	return m.integrityFilter != integrityShowProblems || event.Integrity != integrityVerified
+}
+
+func (m *controlCenterModel) renderIntegrityLine() string {
+	var counts = map[string]int{}
+	for _, event := range m.events {
+		counts[event.Integrity]++
+	}
+	line := fmt.Sprintf("%s %d verified • %s %d unverified • %s %d tampered",
+		integrityBadge(integrityVerified), counts[integrityVerified],
+		integrityBadge(integrityUnverified), counts[integrityUnverified]+counts[""],
+		integrityBadge(integrityTampered), counts[integrityTampered])
+	if m.integrityFilter == integrityShowProblems {
+		line += statusStyle.Render(" • showing unsigned/failed")
+	}
+	if m.integrity.strict {
+		line += busyStyle.Render(fmt.Sprintf(" • strict: verified only, %d hidden", len(m.events)-counts[integrityVerified]))
+	}
+	if len(m.integrity.keys) == 0 {
+		line += statusStyle.Render(" • no trusted keys")
+	}
+	return line + statusStyle.Render(" • i: unsigned/failed • v: strict • R: replay")
+}
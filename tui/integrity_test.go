@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// The fixture is shared with Happen/tests/canonical-json.test.ts
+const canonicalFixturePath = "../Happen/tests/fixtures/canonical-json.json"
+
+type canonicalFixture struct {
+	CanonicalStringify []struct {
+		Name     string `json:"name"`
+		Input    string `json:"input"`
+		Expected string `json:"expected"`
+		SHA256   string `json:"sha256"`
+	} `json:"canonicalStringify"`
+	CalculateEventHash []struct {
+		Name     string `json:"name"`
+		Event    string `json:"event"`
+		Expected string `json:"expected"`
+	} `json:"calculateEventHash"`
+}
+
+func loadCanonicalFixture(t *testing.T) canonicalFixture {
+	t.Helper()
+	data, err := os.ReadFile(canonicalFixturePath)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	var fixture canonicalFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+	return fixture
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	for _, tc := range loadCanonicalFixture(t).CanonicalStringify {
+		t.Run(tc.Name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tc.Input), &value); err != nil {
+				t.Fatal(err)
+			}
+			got, err := canonicalJSON(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.Expected {
+				t.Errorf("canonicalJSON(%s)\n got %s\nwant %s", tc.Input, got, tc.Expected)
+			}
+			sum := sha256.Sum256([]byte(got))
+			if hash := hex.EncodeToString(sum[:]); hash != tc.SHA256 {
+				t.Errorf("sha256 = %s, want %s", hash, tc.SHA256)
+			}
+		})
+	}
+}
+
+func TestEventHash(t *testing.T) {
+	for _, tc := range loadCanonicalFixture(t).CalculateEventHash {
+		t.Run(tc.Name, func(t *testing.T) {
+			var event map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.Event), &event); err != nil {
+				t.Fatal(err)
+			}
+			got, err := eventHash(event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.Expected {
+				t.Errorf("eventHash = %s, want %s", got, tc.Expected)
+			}
+		})
+	}
+}
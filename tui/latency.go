@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sketch parameters: 1% relative accuracy, and at most 2048 buckets before the
+// lowest buckets are collapsed together, which keeps memory bounded per agent.
+const (
+	sketchAccuracy   = 0.01
+	sketchMaxBuckets = 2048
+)
+
+// latencySketch is a log-bucketed quantile sketch in the style of DDSketch.
+// Values are milliseconds; quantiles are accurate to within sketchAccuracy.
+type latencySketch struct {
+	gamma   float64
+	buckets map[int]uint64
+	zeros   uint64
+	count   uint64
+	min     float64
+	max     float64
+}
+
+func newLatencySketch() *latencySketch {
+	return &latencySketch{
+		gamma:   (1 + sketchAccuracy) / (1 - sketchAccuracy),
+		buckets: make(map[int]uint64),
+		min:     math.Inf(1),
+		max:     math.Inf(-1),
+	}
+}
+
+func (s *latencySketch) Add(ms float64) {
+	if math.IsNaN(ms) || ms < 0 {
+		return
+	}
+	s.count++
+	s.min = math.Min(s.min, ms)
+	s.max = math.Max(s.max, ms)
+	if ms < 1e-9 {
+		s.zeros++
+		return
+	}
+	s.buckets[s.index(ms)]++
+	if len(s.buckets) > sketchMaxBuckets {
+		s.collapse()
+	}
+}
+
+func (s *latencySketch) index(ms float64) int {
+	return int(math.Ceil(math.Log(ms) / math.Log(s.gamma)))
+}
+
+// value returns the representative value of a bucket
+func (s *latencySketch) value(index int) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+}
+
+// collapse folds the two lowest buckets together, sacrificing accuracy at the fast end
+func (s *latencySketch) collapse() {
+	keys := s.sortedKeys()
+	s.buckets[keys[1]] += s.buckets[keys[0]]
+	delete(s.buckets, keys[0])
+}
+
+func (s *latencySketch) sortedKeys() []int {
+	keys := make([]int, 0, len(s.buckets))
+	for k := range s.buckets {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Quantile returns the estimated value at q in [0,1], or NaN when empty
+func (s *latencySketch) Quantile(q float64) float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	rank := uint64(q * float64(s.count-1))
+	if rank < s.zeros {
+		return 0
+	}
+	seen := s.zeros
+	for _, k := range s.sortedKeys() {
+		seen += s.buckets[k]
+		if seen > rank {
+			return math.Min(math.Max(s.value(k), s.min), s.max)
+		}
+	}
+	return s.max
+}
+
+// Histogram counts samples into n log-spaced bins between the observed min and max
+func (s *latencySketch) Histogram(n int) (edges []float64, counts []uint64) {
+	if s.count == 0 || n < 1 {
+		return nil, nil
+	}
+	lo, hi := math.Max(s.min, 1), math.Max(s.max, 1)
+	if hi <= lo {
+		hi = lo * 2
+	}
+	edges = make([]float64, n+1)
+	for i := range edges {
+		edges[i] = lo * math.Pow(hi/lo, float64(i)/float64(n))
+	}
+	counts = make([]uint64, n)
+	counts[0] += s.zeros
+	for k, c := range s.buckets {
+		v := s.value(k)
+		bin := sort.SearchFloat64s(edges[1:], v)
+		counts[min(bin, n-1)] += c
+	}
+	return edges, counts
+}
+
+// latencyTracker keeps one sketch per agent plus a system-wide sketch
+type latencyTracker struct {
+	system *latencySketch
+	agents map[string]*latencySketch
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{
+		system: newLatencySketch(),
+		agents: make(map[string]*latencySketch),
+	}
+}
+
+func (t *latencyTracker) record(agentID string, d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+	t.system.Add(ms)
+	sketch, ok := t.agents[agentID]
+	if !ok {
+		sketch = newLatencySketch()
+		t.agents[agentID] = sketch
+	}
+	sketch.Add(ms)
+}
+
+// percentiles formats p50/p90/p99 for an agent, or dashes when nothing was recorded
+func (t *latencyTracker) percentiles(agentID string) (string, string, string) {
+	sketch, ok := t.agents[agentID]
+	if !ok || sketch.count == 0 {
+		return "-", "-", "-"
+	}
+	return formatMillis(sketch.Quantile(0.5)), formatMillis(sketch.Quantile(0.9)), formatMillis(sketch.Quantile(0.99))
+}
+
+func formatMillis(ms float64) string {
+	switch {
+	case math.IsNaN(ms):
+		return "-"
+	case ms >= 10000:
+		return fmt.Sprintf("%.1fs", ms/1000)
+	default:
+		return fmt.Sprintf("%.0fms", ms)
+	}
+}
+
+// renderLatency shows percentiles and a histogram for the system or the charted agent
+func (m *controlCenterModel) renderLatency(width int) string {
+	sketch, scope := m.latency.system, "System"
+	if m.analyticsAgent != "" {
+		scope = "Agent " + m.agentName(m.analyticsAgent)
+		sketch = m.latency.agents[m.analyticsAgent]
+	}
+	if sketch == nil || sketch.count == 0 {
+		return statusStyle.Render(fmt.Sprintf("%s: no task completions recorded yet", scope))
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s  %s %s  %s %s  %s %s  %s %s  %s %d\n\n",
+		statLabelStyle.Render(scope),
+		statLabelStyle.Render("p50"), statValueStyle.Render(formatMillis(sketch.Quantile(0.5))),
+		statLabelStyle.Render("p90"), statValueStyle.Render(formatMillis(sketch.Quantile(0.9))),
+		statLabelStyle.Render("p99"), statValueStyle.Render(formatMillis(sketch.Quantile(0.99))),
+		statLabelStyle.Render("max"), statValueStyle.Render(formatMillis(sketch.max)),
+		statLabelStyle.Render("n"), sketch.count,
+	))
+
+	edges, counts := sketch.Histogram(10)
+	var peak uint64
+	for _, c := range counts {
+		peak = max(peak, c)
+	}
+	barWidth := max(10, width-30)
+	for i, c := range counts {
+		bar := int(float64(c) / float64(peak) * float64(barWidth))
+		content.WriteString(fmt.Sprintf("%8s–%-8s %s %d\n",
+			formatMillis(edges[i]),
+			formatMillis(edges[i+1]),
+			busyStyle.Render(strings.Repeat("█", bar)),
+			c,
+		))
+	}
+	return strings.TrimRight(content.String(), "\n")
+}
+
+// TaskCompletedUpdate reports a single finished task and how long it took
+type TaskCompletedUpdate struct {
+	AgentID  string
+	Duration time.Duration
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"strings"
@@ -62,6 +63,12 @@ type message struct {
 	content  string
 	isAgent  bool
 	markdown bool
+
+	// Messages sharing a correlation ID are shown as one thread
+	at            time.Time
+	correlationID string
+	causationID   string
+	kind          string // kindPeer, kindRequest or empty for prompts and replies
 }
 
 type agentStatus int
@@ -112,6 +119,23 @@ type model struct {
 	width       int
 	height      int
 	ready       bool
+
+	notifications notificationCenter
+	notifier      *notifyDispatcher
+	wsClient      bridgeTransport
+
+	// bridgeAgents are the agent IDs the bridge can route prompts to, learned from agent:spawn
+	bridgeAgents map[string]bool
+
+	// Agent-to-agent chatter, kept out of the conversation viewports
+	background       map[string][]backgroundActivity
+	backgroundUnseen map[string]int
+	showBackground   bool
+
+	// Conversation threads by correlation ID
+	threadCursor     map[string]string // agent ID -> current thread
+	collapsedThreads map[string]bool
+	threadNav        threadNavigator
 }
 
 func initialModel() model {
@@ -168,14 +192,22 @@ func initialModel() model {
 		spinner:     s,
 		renderer:    renderer,
 		activeAgent: 0,
+
+		notifications:    newNotificationCenter(),
+		background:       make(map[string][]backgroundActivity),
+		backgroundUnseen: make(map[string]int),
+		bridgeAgents:     make(map[string]bool),
+		threadCursor:     make(map[string]string),
+		collapsedThreads: make(map[string]bool),
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
-		m.spinner.Tick,
-		textarea.Blink,
-	)
+	cmds := []tea.Cmd{m.spinner.Tick, textarea.Blink}
+	if m.wsClient != nil {
+		cmds = append(cmds, listenBridge(m.wsClient))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -207,32 +239,91 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.ready = true
 
 	case tea.KeyMsg:
+		if m.notifications.open && msg.Type != tea.KeyCtrlC {
+			return m, m.handleNotificationKey(msg)
+		}
+		if m.threadNav.open && msg.Type != tea.KeyCtrlC {
+			m.handleThreadNavKey(msg)
+			return m, nil
+		}
+		switch msg.String() {
+		case "alt+up":
+			m.stepThread(-1)
+			return m, nil
+		case "alt+down":
+			m.stepThread(1)
+			return m, nil
+		}
 		switch msg.Type {
+		case tea.KeyCtrlT:
+			m.openThreadNavigator()
+			return m, nil
+		case tea.KeyCtrlO:
+			m.toggleThread()
+			return m, nil
+		case tea.KeyCtrlN:
+			m.notifications.open = true
+			return m, nil
+		case tea.KeyCtrlB:
+			m.showBackground = !m.showBackground
+			if m.showBackground {
+				delete(m.backgroundUnseen, m.agents[m.activeAgent].id)
+			}
+			return m, nil
 		case tea.KeyCtrlC, tea.KeyEsc:
 			return m, tea.Quit
 		case tea.KeyTab:
 			// Switch active agent
 			m.activeAgent = (m.activeAgent + 1) % len(m.agents)
 			m.agentList.Select(m.activeAgent)
+			if m.showBackground {
+				delete(m.backgroundUnseen, m.agents[m.activeAgent].id)
+			}
 		case tea.KeyEnter:
 			// Send message to active agent
 			if m.input.Value() != "" {
 				agent := &m.agents[m.activeAgent]
-				agent.messages = append(agent.messages, message{
-					content: m.input.Value(),
-					isAgent: false,
-				})
+				prompt := message{
+					content:       m.input.Value(),
+					isAgent:       false,
+					at:            time.Now(),
+					correlationID: newCorrelationID(),
+				}
+				agent.messages = append(agent.messages, prompt)
 				agent.status = "processing"
+				m.threadCursor[agent.id] = prompt.correlationID
 				
 				// Update viewport
-				vp := m.viewports[agent.id]
-				vp.SetContent(renderMessages(agent.messages, m.renderer))
-				m.viewports[agent.id] = vp
+				m.refreshConversation(m.activeAgent, true)
 				
 				m.input.Reset()
 				
-				// Simulate response after delay
-				cmds = append(cmds, simulateResponse(m.activeAgent))
+				// Without a bridge simulate a response after a delay; with one, say why a prompt could not go out
+				var failure string
+				switch {
+				case m.wsClient == nil:
+					cmds = append(cmds, simulateResponse(m.activeAgent, prompt.correlationID))
+				case !m.bridgeAgents[agent.id]:
+					failure = fmt.Sprintf("%s is not connected to the bridge", agent.name)
+				default:
+					if err := m.wsClient.Send("agent:message", map[string]interface{}{
+						"agentId":       agent.id,
+						"content":       prompt.content,
+						"correlationId": prompt.correlationID,
+					}); err != nil {
+						failure = fmt.Sprintf("send failed: %v", err)
+					}
+				}
+				if failure != "" {
+					agent.messages = append(agent.messages, message{
+						content:       "⚠ " + failure,
+						isAgent:       true,
+						at:            time.Now(),
+						correlationID: prompt.correlationID,
+					})
+					agent.status = "ready"
+					m.refreshConversation(m.activeAgent, true)
+				}
 			}
 		}
 
@@ -241,55 +332,99 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.agentIndex < len(m.agents) {
 			agent := &m.agents[msg.agentIndex]
 			agent.messages = append(agent.messages, message{
-				content:  msg.response,
-				isAgent:  true,
-				markdown: true,
+				content:       msg.response,
+				isAgent:       true,
+				markdown:      true,
+				at:            time.Now(),
+				correlationID: msg.correlationID,
 			})
 			agent.status = "ready"
 			
 			// Update viewport
-			vp := m.viewports[agent.id]
-			vp.SetContent(renderMessages(agent.messages, m.renderer))
-			vp.GotoBottom()
-			m.viewports[agent.id] = vp
+			m.refreshConversation(msg.agentIndex, true)
 		}
 
+	case agentReplyMsg:
+		for i, agent := range m.agents {
+			if agent.id == msg.agentId {
+				m.agents[i].messages = append(m.agents[i].messages, message{
+					content:       msg.content,
+					isAgent:       true,
+					markdown:      true,
+					at:            time.Now(),
+					correlationID: msg.correlationID,
+					causationID:   msg.causationID,
+				})
+				m.agents[i].status = "ready"
+				m.refreshConversation(i, true)
+				break
+			}
+		}
+
+	case agentSpawnMsg:
+		m.addBridgeAgent(msg.agentId, msg.name)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
 
+	case bridgeMsg:
+		cmds = append(cmds, listenBridge(m.wsClient))
+		if msg.inner != nil {
+			updated, cmd := m.Update(msg.inner)
+			m = updated.(model)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+
+	case backgroundMsg:
+		m.addBackground(msg.activity)
+		m.threadPeerActivity(msg.activity)
+
+	case snoozeEndedMsg:
+		m.syncNotificationLevels()
+
+	case sinkResultMsg:
+		m.notifier.record(msg)
+
 	case notificationMsg:
-		// Handle notification updates
+		// Record every notification; a snooze only holds back the border and the sinks
+		snoozed := m.notifications.snoozed(msg.agentId, time.Now())
+		m.notifications.add(&notification{
+			agentID: msg.agentId,
+			level:   msg.notificationLevel,
+			at:      time.Now(),
+			message: msg.message,
+			pending: msg.pendingRequests,
+			snoozed: snoozed,
+		})
+		if !snoozed {
+			cmds = append(cmds, m.notifier.dispatch(msg.agentId, m.agentDisplayName(msg.agentId), msg.notificationLevel, msg.message, msg.pendingRequests, time.Now()))
+		}
 		for i, agent := range m.agents {
 			if agent.id == msg.agentId {
-				m.agents[i].notificationLevel = msg.notificationLevel
 				m.agents[i].pendingRequests = msg.pendingRequests
 				
-				// Add notification message to agent's history
+				// Notifications are requests for the human whatever their level; peer traffic arrives as agent:background
 				if msg.message != "" {
 					m.agents[i].messages = append(m.agents[i].messages, message{
-						content:  fmt.Sprintf("📢 %s", msg.message),
-						isAgent:  true,
-						markdown: false,
+						content:       fmt.Sprintf("📢 %s", msg.message),
+						isAgent:       true,
+						markdown:      false,
+						at:            time.Now(),
+						correlationID: msg.correlationID,
+						causationID:   msg.causationID,
+						kind:          kindRequest,
 					})
 					
 					// Update viewport
-					vp := m.viewports[agent.id]
-					vp.SetContent(renderMessages(m.agents[i].messages, m.renderer))
-					vp.GotoBottom()
-					m.viewports[agent.id] = vp
+					m.refreshConversation(i, true)
 				}
-				
-				// Update agent list
-				items := make([]list.Item, len(m.agents))
-				for j, a := range m.agents {
-					items[j] = a
-				}
-				m.agentList.SetItems(items)
 				break
 			}
 		}
+		m.syncNotificationLevels()
 	}
 
 	// Update components
@@ -337,9 +472,14 @@ func (m model) View() string {
 		agent := m.agents[m.activeAgent]
 		vp := m.viewports[agent.id]
 		
-		// Update viewport size to use full space
+		// Update viewport size to use full space, less the background panel when shown
 		vp.Width = rightWidth - 4
 		vp.Height = viewportHeight - 2
+		panelWidth := 0
+		if m.showBackground {
+			panelWidth = max(24, (rightWidth-4)*9/20)
+			vp.Width -= panelWidth + 1
+		}
 		
 		// Choose border style based on notification level
 		var borderStyle lipgloss.Style
@@ -358,15 +498,37 @@ func (m model) View() string {
 			notificationBadge = " " + notificationBadgeStyle.Render(fmt.Sprintf(" %d pending ", agent.pendingRequests))
 		}
 		
-		agentHeader := fmt.Sprintf("%s %s%s",
+		backgroundBadge := ""
+		if unseen := m.backgroundUnseen[agent.id]; unseen > 0 && !m.showBackground {
+			backgroundBadge = statusStyle.Render(fmt.Sprintf(" · 💬 %d background", unseen))
+		}
+		
+		agentHeader := fmt.Sprintf("%s %s%s%s",
 			agentStyle.Render(agent.name),
 			statusStyle.Render(fmt.Sprintf("(%s)", agent.status)),
-			notificationBadge)
+			notificationBadge,
+			backgroundBadge)
+		
+		body := fmt.Sprintf("%s\n\n%s", agentHeader, vp.View())
+		if m.showBackground {
+			panel := inactiveStyle.
+				Width(panelWidth - 2).
+				Height(viewportHeight - 4).
+				Render(m.renderBackgroundPanel(agent.id, panelWidth-4, viewportHeight-4))
+			conversation := lipgloss.NewStyle().Width(vp.Width + 1).Render(vp.View())
+			body = fmt.Sprintf("%s\n\n%s", agentHeader, lipgloss.JoinHorizontal(lipgloss.Top, conversation, panel))
+		}
+		if m.threadNav.open {
+			body = m.renderThreadNavigator(rightWidth-4, viewportHeight)
+		}
+		if m.notifications.open {
+			body = m.renderNotificationCenter(rightWidth-4, viewportHeight)
+		}
 		
 		agentView = borderStyle.
 			Width(rightWidth - 2).
 			Height(viewportHeight).
-			Render(fmt.Sprintf("%s\n\n%s", agentHeader, vp.View()))
+			Render(body)
 	} else {
 		agentView = inactiveStyle.
 			Width(rightWidth - 2).
@@ -408,7 +570,11 @@ func (m model) View() string {
 		}
 	}
 	
-	status := statusStyle.Render(fmt.Sprintf(" %d agents%s • Tab: switch • Enter: send • Ctrl+C: quit", len(m.agents), notificationStatus))
+	if unacked := m.notifications.unacknowledged(); unacked > 0 {
+		notificationStatus += fmt.Sprintf(" • 📬 %d unacknowledged", unacked)
+	}
+	
+	status := statusStyle.Render(truncate(fmt.Sprintf(" %d agents%s • Tab: switch • Enter: send • Ctrl+T: threads • Ctrl+N: notifications • Ctrl+B: background • Ctrl+C: quit", len(m.agents), notificationStatus), m.width))
 	
 	// Final layout
 	main := lipgloss.JoinHorizontal(lipgloss.Top, leftPanel, rightPanel)
@@ -418,8 +584,9 @@ func (m model) View() string {
 
 // Message types
 type responseMsg struct {
-	agentIndex int
-	response   string
+	agentIndex    int
+	response      string
+	correlationID string
 }
 
 type notificationMsg struct {
@@ -427,6 +594,45 @@ type notificationMsg struct {
 	notificationLevel agentStatus
 	pendingRequests  int
 	message          string
+	requestID        string
+	correlationID    string
+	causationID      string
+}
+
+// agentReplyMsg is an agent:response from the bridge
+type agentReplyMsg struct {
+	agentId       string
+	content       string
+	correlationID string
+	causationID   string
+}
+
+// agentSpawnMsg is an agent:spawn from the bridge
+type agentSpawnMsg struct {
+	agentId string
+	name    string
+}
+
+// addBridgeAgent marks an agent as reachable through the bridge, adding it to the list if it is new
+func (m *model) addBridgeAgent(id, name string) {
+	m.bridgeAgents[id] = true
+	for _, a := range m.agents {
+		if a.id == id {
+			return
+		}
+	}
+
+	a := agent{id: id, name: name, status: "ready", notificationLevel: statusNormal}
+	m.agents = append(m.agents, a)
+	m.agentList.InsertItem(len(m.agents)-1, a)
+
+	vp := viewport.New(0, 0)
+	if m.ready {
+		listWidth := m.width / 4
+		vp.Width = m.width - listWidth - 6
+		vp.Height = m.height - 12
+	}
+	m.viewports[id] = vp
 }
 
 // Render messages with glamour for markdown
@@ -453,7 +659,7 @@ func renderMessages(messages []message, renderer *glamour.TermRenderer) string {
 }
 
 // Simulate agent response with markdown
-func simulateResponse(agentIndex int) tea.Cmd {
+func simulateResponse(agentIndex int, correlationID string) tea.Cmd {
 	return func() tea.Msg {
 		time.Sleep(2 * time.Second)
 		responses := []string{
@@ -463,8 +669,9 @@ func simulateResponse(agentIndex int) tea.Cmd {
 			"## Insights\n\nBased on the analysis:\n\n* Pattern recognition shows **strong correlation**\n* The data suggests a `positive trend`\n* Further investigation recommended\n\n[View detailed report](#)",
 		}
 		return responseMsg{
-			agentIndex: agentIndex,
-			response:   responses[agentIndex%len(responses)],
+			agentIndex:    agentIndex,
+			response:      responses[agentIndex%len(responses)],
+			correlationID: correlationID,
 		}
 	}
 }
@@ -477,7 +684,89 @@ func min(a, b int) int {
 }
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	controlCenter := flag.Bool("control-center", false, "run the multi-agent control center instead of the chat view")
+	bridgeURL := flag.String("bridge", "ws://localhost:8080", "WebSocket bridge URL, or nats:// (tls://) to read a Happen mesh directly; the chat view uses it for agent notifications when available")
+	alertRules := flag.String("alerts", "", "JSON file of control center alert rules (defaults built in)")
+	staleTTL := flag.Duration("heartbeat-stale", defaultStaleTTL, "heartbeat age after which an agent is shown as stale")
+	offlineTTL := flag.Duration("heartbeat-offline", defaultOfflineTTL, "heartbeat age after which an agent is shown as offline")
+	eventLog := flag.String("event-log", "", "JSON lines file that persists control center events for the activity heatmap")
+	autoRules := flag.String("auto-rules", "", "JSON file of auto-approval rules for human requests; rules added in the TUI are saved here")
+	auditLogPath := flag.String("audit-log", defaultAuditLogPath(), "hash-chained JSON lines log of every human response sent from the control center")
+	operator := flag.String("operator", currentOperator(), "name recorded in the audit log for responses given in the control center")
+	slaTargets := flag.String("sla", "", "human response targets, e.g. 2m or high=30s,medium=2m,low=10m,default=5m")
+	traceSlow := flag.Duration("trace-slow", defaultTraceSlow, "span duration from which the trace waterfall highlights a span as slow")
+	notifySinks := flag.String("notify", "", "JSON file of external notification sinks (bell, osc9, osc777, exec, webhook, smtp)")
+	trustedKeys := flag.String("trusted-keys", "", "PEM file of public keys trusted to sign Happen events (EventIntegrity)")
+	strictIntegrity := flag.Bool("strict-integrity", false, "show only events whose integrity signature verifies against a trusted key")
+	flag.Parse()
+
+	sinks, err := LoadNotifySinks(*notifySinks)
+	if err != nil {
+		log.Fatal(err)
+	}
+	
+	chat := initialModel()
+	chat.notifier = newNotifyDispatcher(sinks)
+	var root tea.Model = chat
+	if !*controlCenter {
+		// The chat view works without a bridge; when one is running it delivers agent notifications
+		if client, err := dialBridge(*bridgeURL); err == nil {
+			defer client.Close()
+			chat.wsClient = client
+			root = chat
+		}
+	} else {
+		cc := initialControlCenterModel()
+		rules, err := LoadAlertRules(*alertRules)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cc.alerts = newAlertEngine(rules)
+		cc.heartbeats = newHeartbeatTracker(*staleTTL, *offlineTTL)
+		if cc.autoRules, err = LoadAutoRules(*autoRules); err != nil {
+			log.Fatal(err)
+		}
+		cc.autoRulesFile = *autoRules
+		targets, fallback, err := parseSLATargets(*slaTargets)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cc.sla = newSLATracker(targets, fallback)
+		cc.traces = newTraceStore(*traceSlow)
+		cc.notifier = newNotifyDispatcher(sinks)
+		keys, err := LoadTrustedKeys(*trustedKeys)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cc.integrity = &integrityVerifier{keys: keys, strict: *strictIntegrity}
+		audit, err := openAuditLog(*auditLogPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer audit.Close()
+		cc.audit = audit
+		cc.operator = *operator
+		store, err := openEventStore(*eventLog)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer store.Close()
+		cc.eventLog = store
+		cc.events = store.recent(100)
+		for _, event := range store.events {
+			cc.traces.add(event)
+		}
+		cc.updateEventView()
+		if client, err := dialBridge(*bridgeURL); err != nil {
+			log.Printf("bridge unavailable at %s: %v", *bridgeURL, err)
+		} else {
+			defer client.Close()
+			cc.wsClient = client
+		}
+		root = cc
+	}
+
+	p := tea.NewProgram(root, tea.WithAltScreen(), tea.WithOutput(terminalOutput))
 	if _, err := p.Run(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// A Happen event packed the way msgpackr's default pack() writes it: string-keyed maps, the
+// smallest integer forms, float64 for fractions and integers past 32 bits, str8 for strings
+// of 32-255 bytes, and Dates as the timestamp extension (32-bit when there are no
+// milliseconds, 64-bit otherwise). It is assembled from the msgpack spec rather than captured
+// from msgpackr itself, because the sandbox running these tests has no node_modules.
+const msgpackrEvent = "84" +
+	"a26964" + "a56576742d31" + // id: "evt-1"
+	"a474797065" + "ad6f726465722e63726561746564" + // type: "order.created"
+	"a77061796c6f6164" + "8a" + // payload: map(10)
+	"a5746f74616c" + "cb4045400000000000" + // total: 42.5
+	"a3717479" + "03" + // qty: 3
+	"a5636f756e74" + "ccc8" + // count: 200
+	"a564656c7461" + "d1ff38" + // delta: -200
+	"a5736d616c6c" + "fb" + // small: -5
+	"a3626967" + "cb4278fcf690433000" + // big: 1717171717171
+	"a26f6b" + "c3" + // ok: true
+	"a46e6f7465" + "c0" + // note: null
+	"a474616773" + "92a161a162" + // tags: ["a", "b"]
+	"a773756d6d617279" + "d928" + hexSummary + // summary: str8
+	"a7636f6e74657874" + "81" + // context: map(1)
+	"a663617573616c" + "85" + // causal: map(5)
+	"a26964" + "a56576742d31" +
+	"a673656e646572" + "a66f7264657273" +
+	"a470617468" + "92a8636865636b6f7574a66f7264657273" +
+	"a974696d657374616d70" + "d7ff28c503006659f605" + // timestamp: Date, 64-bit
+	"a87265636569766564" + "d6ff6659f605" // received: Date, 32-bit
+
+const hexSummary = "666f727479206368617261637465722073756d6d617279206f662074686973206f726465722e2e2e"
+
+func TestDecodeMsgpackrEvent(t *testing.T) {
+	data, err := hex.DecodeString(msgpackrEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeHappenPayload(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := map[string]interface{}{
+		"id":   "evt-1",
+		"type": "order.created",
+		"payload": map[string]interface{}{
+			"total":   42.5,
+			"qty":     3.0,
+			"count":   200.0,
+			"delta":   -200.0,
+			"small":   -5.0,
+			"big":     1717171717171.0,
+			"ok":      true,
+			"note":    nil,
+			"tags":    []interface{}{"a", "b"},
+			"summary": "forty character summary of this order...",
+		},
+		"context": map[string]interface{}{
+			"causal": map[string]interface{}{
+				"id":        "evt-1",
+				"sender":    "orders",
+				"path":      []interface{}{"checkout", "orders"},
+				"timestamp": "2024-05-31T16:08:37.171Z",
+				"received":  "2024-05-31T16:08:37.000Z",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestDecodeHappenPayloadJSON(t *testing.T) {
+	got, err := decodeHappenPayload([]byte(` {"type":"order.created","payload":[1,2]}` + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"type": "order.created", "payload": []interface{}{1.0, 2.0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %#v", got)
+	}
+}
+
+func TestDecodeMsgpackErrors(t *testing.T) {
+	for _, input := range []string{
+		"",           // empty
+		"82a161",     // map cut short
+		"d9",         // str8 without a length
+		"a361626301", // "abc" and a trailing byte
+		"c1",         // never used
+	} {
+		data, _ := hex.DecodeString(input)
+		if _, err := decodeHappenPayload(data); err == nil {
+			t.Errorf("decodeHappenPayload(%x) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	value := map[string]interface{}{
+		"s":     "x",
+		"n":     []interface{}{0.0, 127.0, 128.0, 65536.0, -1.0, -33.0, -129.0, -40000.0, 1.5, 4294967296.0},
+		"b":     false,
+		"empty": map[string]interface{}{},
+	}
+	data, err := encodeMsgpack(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decodeHappenPayload(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, value) {
+		t.Errorf("round trip gave %#v", got)
+	}
+}
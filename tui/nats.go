@@ -0,0 +1,480 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cabal/tui/happen"
+)
+
+// bridgeTransport is what the TUIs need from their link to the agents: the Node bridge
+// over WebSocket, or a Happen mesh read straight off NATS
+type bridgeTransport interface {
+	Send(msgType string, payload interface{}) error
+	Receive() <-chan WSMessage
+	Close()
+}
+
+// dialBridge picks the transport from the URL scheme
+func dialBridge(rawURL string) (bridgeTransport, error) {
+	var client bridgeTransport
+	var err error
+	if strings.HasPrefix(rawURL, "nats://") || strings.HasPrefix(rawURL, "tls://") {
+		client, err = NewNATSClient(rawURL)
+	} else {
+		client, err = NewWSClient(rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+const natsTimeout = 5 * time.Second
+
+// tuiNodeID is the sender on events the TUI publishes
+const tuiNodeID = "cabal-tui"
+
+// natsConn speaks the core NATS client protocol: just enough to subscribe and publish
+type natsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu      sync.Mutex // guards writes and subs
+	subs    map[int]func(subject, reply string, data []byte)
+	nextSID int
+
+	done chan struct{}
+	err  error // why the read loop stopped
+}
+
+// serverInfo is the part of the server's INFO line the client acts on
+type serverInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	Headers     bool `json:"headers"`
+}
+
+func dialNATS(rawURL string) (*natsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	conn, err := net.DialTimeout("tcp", host, natsTimeout)
+	if err != nil {
+		return nil, err
+	}
+	nc := &natsConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		subs:   make(map[int]func(string, string, []byte)),
+		done:   make(chan struct{}),
+	}
+	if err := nc.handshake(u); err != nil {
+		nc.conn.Close()
+		return nil, fmt.Errorf("nats %s: %w", host, err)
+	}
+	go nc.readLoop()
+	return nc, nil
+}
+
+// handshake reads INFO, upgrades to TLS when asked, sends CONNECT and waits for the PONG
+// that confirms the server accepted it
+func (nc *natsConn) handshake(u *url.URL) error {
+	nc.conn.SetDeadline(time.Now().Add(natsTimeout))
+	defer nc.conn.SetDeadline(time.Time{})
+
+	line, err := nc.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	op, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if op != "INFO" {
+		return fmt.Errorf("expected INFO, got %q", truncate(line, 40))
+	}
+	var info serverInfo
+	if err := json.Unmarshal([]byte(args), &info); err != nil {
+		return fmt.Errorf("INFO: %w", err)
+	}
+
+	if info.TLSRequired || u.Scheme == "tls" {
+		tlsConn := tls.Client(nc.conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			return err
+		}
+		nc.conn = tlsConn
+		nc.reader = bufio.NewReader(tlsConn)
+	}
+
+	connect := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"lang":     "go",
+		"name":     tuiNodeID,
+		"protocol": 1,
+		// With headers on, messages that carry them arrive as HMSG, which the read loop handles
+		"headers": info.Headers,
+	}
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			connect["user"], connect["pass"] = u.User.Username(), password
+		} else {
+			connect["auth_token"] = u.User.Username()
+		}
+	}
+	data, _ := json.Marshal(connect)
+	if _, err := fmt.Fprintf(nc.conn, "CONNECT %s\r\nPING\r\n", data); err != nil {
+		return err
+	}
+	for {
+		line, err := nc.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New(strings.Trim(strings.TrimPrefix(line, "-ERR "), "'"))
+		}
+	}
+}
+
+func (nc *natsConn) write(format string, args ...interface{}) error {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	_, err := fmt.Fprintf(nc.conn, format, args...)
+	return err
+}
+
+// subscribe registers handler for subject; handlers run on the read loop and must not block
+func (nc *natsConn) subscribe(subject string, handler func(subject, reply string, data []byte)) (int, error) {
+	nc.mu.Lock()
+	nc.nextSID++
+	sid := nc.nextSID
+	nc.subs[sid] = handler
+	nc.mu.Unlock()
+	return sid, nc.write("SUB %s %d\r\n", subject, sid)
+}
+
+func (nc *natsConn) unsubscribe(sid int) error {
+	nc.mu.Lock()
+	delete(nc.subs, sid)
+	nc.mu.Unlock()
+	return nc.write("UNSUB %d\r\n", sid)
+}
+
+// request publishes with a one-off inbox as the reply subject and waits for the first answer
+func (nc *natsConn) request(subject string, data []byte, timeout time.Duration) ([]byte, error) {
+	inbox := newInbox()
+	reply := make(chan []byte, 1)
+	sid, err := nc.subscribe(inbox, func(_, _ string, data []byte) {
+		select {
+		case reply <- data:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer nc.unsubscribe(sid)
+	if err := nc.publish(subject, inbox, data); err != nil {
+		return nil, err
+	}
+	select {
+	case data := <-reply:
+		return data, nil
+	case <-nc.done:
+		return nil, fmt.Errorf("nats connection closed: %v", nc.err)
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("%s: no reply within %s", subject, timeout)
+	}
+}
+
+var inboxSeq atomic.Uint64
+
+func newInbox() string {
+	return fmt.Sprintf("_INBOX.cabal.%d.%d", time.Now().UnixNano(), inboxSeq.Add(1))
+}
+
+func (nc *natsConn) publish(subject, reply string, data []byte) error {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	var err error
+	if reply != "" {
+		_, err = fmt.Fprintf(nc.conn, "PUB %s %s %d\r\n", subject, reply, len(data))
+	} else {
+		_, err = fmt.Fprintf(nc.conn, "PUB %s %d\r\n", subject, len(data))
+	}
+	if err == nil {
+		_, err = nc.conn.Write(append(data, '\r', '\n'))
+	}
+	return err
+}
+
+func (nc *natsConn) readLoop() {
+	defer close(nc.done)
+	for {
+		line, err := nc.reader.ReadString('\n')
+		if err != nil {
+			nc.err = err
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch strings.ToUpper(op) {
+		case "MSG", "HMSG":
+			if err := nc.deliver(args, strings.ToUpper(op) == "HMSG"); err != nil {
+				nc.err = err
+				return
+			}
+		case "PING":
+			nc.write("PONG\r\n")
+		case "-ERR":
+			log.Printf("nats: %s", args)
+		}
+	}
+}
+
+// deliver reads one message body and hands it to its subscription:
+//
+//	MSG <subject> <sid> [reply-to] <#bytes>
+//	HMSG <subject> <sid> [reply-to] <#header bytes> <#total bytes>
+//
+// Headers are skipped; handlers only see the payload.
+func (nc *natsConn) deliver(args string, headers bool) error {
+	fields := strings.Fields(args)
+	sizes := 1
+	if headers {
+		sizes = 2
+	}
+	if len(fields) != 2+sizes && len(fields) != 3+sizes {
+		return fmt.Errorf("malformed MSG %q", args)
+	}
+	total, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || total < 0 {
+		return fmt.Errorf("malformed MSG %q", args)
+	}
+	header := 0
+	if headers {
+		if header, err = strconv.Atoi(fields[len(fields)-2]); err != nil || header < 0 || header > total {
+			return fmt.Errorf("malformed HMSG %q", args)
+		}
+	}
+	sid, _ := strconv.Atoi(fields[1])
+	reply := ""
+	if len(fields) == 3+sizes {
+		reply = fields[2]
+	}
+	data := make([]byte, total+2)
+	if _, err := io.ReadFull(nc.reader, data); err != nil {
+		return err
+	}
+	nc.mu.Lock()
+	handler := nc.subs[sid]
+	nc.mu.Unlock()
+	if handler != nil {
+		handler(fields[0], reply, data[header:total])
+	}
+	return nil
+}
+
+func (nc *natsConn) close() {
+	nc.conn.Close()
+	<-nc.done
+}
+
+// NATSClient observes a Happen mesh directly. Messages on Happen's subjects are turned into
+// the same frames the Node bridge sends, so both TUIs decode them unchanged.
+type NATSClient struct {
+	nc      *natsConn
+	receive chan WSMessage
+	mapper  *happenMapper // only the read loop touches it
+}
+
+// NewNATSClient connects to nats://[user:pass@]host:port (tls:// for TLS) and subscribes to
+// Happen's event, system and metrics subjects
+func NewNATSClient(rawURL string) (*NATSClient, error) {
+	nc, err := dialNATS(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	c := &NATSClient{nc: nc, receive: make(chan WSMessage, 256), mapper: newHappenMapper()}
+	for _, subject := range []string{happen.Events + ".>", happen.System + ".>", happen.Metrics + ".>"} {
+		if _, err := nc.subscribe(subject, c.handle); err != nil {
+			nc.close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *NATSClient) Receive() <-chan WSMessage {
+	return c.receive
+}
+
+// Send publishes a TUI command as a Happen event of the same type, on the subject a Happen
+// node would route it to
+func (c *NATSClient) Send(msgType string, payload interface{}) error {
+	now := time.Now()
+	id := fmt.Sprintf("tui-%d", now.UnixNano())
+	data, err := encodeMsgpack(map[string]interface{}{
+		"id":      id,
+		"type":    msgType,
+		"payload": payload,
+		"context": map[string]interface{}{
+			"causal": map[string]interface{}{
+				"id":     id,
+				"sender": tuiNodeID,
+				"path":   []string{tuiNodeID},
+			},
+			"timestamp": now.UnixMilli(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.nc.publish(happen.SubjectForEvent(msgType, tuiNodeID, ""), "", data); err != nil {
+		return err
+	}
+	// A Happen mesh does not acknowledge events, so a published task counts as accepted
+	if task, ok := payload.(map[string]interface{}); ok && msgType == "task:submit" {
+		c.emit(WSMessage{Type: "task:accepted", Payload: map[string]interface{}{
+			"id":          task["id"],
+			"agentId":     task["agentId"],
+			"description": task["description"],
+		}})
+	}
+	return nil
+}
+
+func (c *NATSClient) Close() {
+	c.nc.close()
+}
+
+func (c *NATSClient) emit(frame WSMessage) {
+	select {
+	case c.receive <- frame:
+	default:
+		// Channel full, drop message
+	}
+}
+
+// handle maps one live NATS message to bridge frames
+func (c *NATSClient) handle(subject, _ string, data []byte) {
+	body, err := decodeHappenPayload(data)
+	if err != nil {
+		return
+	}
+	for _, frame := range c.mapper.frames(subject, body, time.Now()) {
+		c.emit(frame)
+	}
+}
+
+// happenMapper turns messages on Happen's subjects into bridge frames
+type happenMapper struct {
+	nodes map[string]string // node ID to last reported status
+}
+
+func newHappenMapper() *happenMapper {
+	return &happenMapper{nodes: make(map[string]string)}
+}
+
+// frames maps one message body received at the given time
+func (hm *happenMapper) frames(subject string, body interface{}, at time.Time) []WSMessage {
+	switch {
+	case strings.HasPrefix(subject, happen.NodeStatus+"."):
+		return hm.nodeStatus(strings.TrimPrefix(subject, happen.NodeStatus+"."), body, at)
+	case strings.HasPrefix(subject, happen.Metrics+"."):
+		return []WSMessage{{Type: "stats", Payload: happenPayload(body)}}
+	case strings.HasPrefix(subject, happen.NodeEvents+"."):
+		// happen.events.node.{nodeId}.{eventType}
+		node, _, _ := strings.Cut(strings.TrimPrefix(subject, happen.NodeEvents+"."), ".")
+		return append(hm.sender(body, at), happenEventFrame(body, node))
+	}
+	return append(hm.sender(body, at), happenEventFrame(body, ""))
+}
+
+// sender counts an event as a sign of life from the node that sent it. Happen nodes do not
+// publish node status on their own, so senders are how the Agents tab learns the mesh.
+func (hm *happenMapper) sender(body interface{}, at time.Time) []WSMessage {
+	node := ""
+	if event, ok := body.(map[string]interface{}); ok {
+		if context, ok := event["context"].(map[string]interface{}); ok {
+			if causal, ok := context["causal"].(map[string]interface{}); ok {
+				node, _ = causal["sender"].(string)
+			}
+		}
+	}
+	if node == "" || node == tuiNodeID {
+		return nil
+	}
+	if _, known := hm.nodes[node]; known {
+		return []WSMessage{{Type: "agent:heartbeat", Payload: map[string]interface{}{"agentId": node, "timestamp": at.UnixMilli()}}}
+	}
+	return hm.nodeStatus(node, nil, at)
+}
+
+// nodeStatus reports a node's heartbeat, and the node list whenever it changes
+func (hm *happenMapper) nodeStatus(node string, body interface{}, at time.Time) []WSMessage {
+	status := "online"
+	if fields, ok := happenPayload(body).(map[string]interface{}); ok {
+		if s, ok := fields["status"].(string); ok && s != "" {
+			status = s
+		}
+	}
+	frames := []WSMessage{{Type: "agent:heartbeat", Payload: map[string]interface{}{"agentId": node, "timestamp": at.UnixMilli()}}}
+	if hm.nodes[node] == status {
+		return frames
+	}
+	hm.nodes[node] = status
+	agents := make([]map[string]interface{}, 0, len(hm.nodes))
+	for id, s := range hm.nodes {
+		agents = append(agents, map[string]interface{}{"id": id, "name": id, "type": "happen-node", "status": s})
+	}
+	return append(frames, WSMessage{Type: "registry:update", Payload: map[string]interface{}{"agents": agents}})
+}
+
+// happenPayload unwraps a Happen event to its payload; other bodies pass through
+func happenPayload(body interface{}) interface{} {
+	if event, ok := body.(map[string]interface{}); ok {
+		if _, isEvent := event["context"]; isEvent {
+			if payload, ok := event["payload"]; ok {
+				return payload
+			}
+		}
+	}
+	return body
+}
+
+// happenEventFrame shapes a Happen event like the bridge's event frame, keeping the original
+// event under data so causal context and integrity are read from it as usual
+func happenEventFrame(body interface{}, to string) WSMessage {
+	event, _ := body.(map[string]interface{})
+	frame := map[string]interface{}{"to": to, "data": body}
+	if event != nil {
+		frame["type"] = event["type"]
+		frame["message"] = truncate(backgroundContent(event["payload"]), 200)
+		if context, ok := event["context"].(map[string]interface{}); ok {
+			frame["timestamp"] = context["timestamp"]
+			if causal, ok := context["causal"].(map[string]interface{}); ok {
+				frame["from"] = causal["sender"]
+			}
+		}
+	}
+	return WSMessage{Type: "event", Payload: frame}
+}
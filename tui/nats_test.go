@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+type natsDelivery struct {
+	subject, reply, data string
+}
+
+func collect(deliveries chan<- natsDelivery) func(subject, reply string, data []byte) {
+	return func(subject, reply string, data []byte) {
+		deliveries <- natsDelivery{subject, reply, string(data)}
+	}
+}
+
+func nextDelivery(t *testing.T, deliveries <-chan natsDelivery) natsDelivery {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message delivered")
+	}
+	return natsDelivery{}
+}
+
+// TestNATSProtocol drives the client against a scripted server, so it runs without nats-server
+func TestNATSProtocol(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	connect := make(chan map[string]interface{}, 1)
+	pong := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		readLine := func() string {
+			line, _ := r.ReadString('\n')
+			return strings.TrimSpace(line)
+		}
+
+		io.WriteString(conn, `INFO {"server_id":"test","headers":true,"max_payload":1048576}`+"\r\n")
+		var options map[string]interface{}
+		json.Unmarshal([]byte(strings.TrimPrefix(readLine(), "CONNECT ")), &options)
+		connect <- options
+		if readLine() != "PING" {
+			return
+		}
+		io.WriteString(conn, "PONG\r\n")
+
+		if !strings.HasPrefix(readLine(), "SUB happen.events.> 1") {
+			return
+		}
+		headers := "NATS/1.0\r\nNats-Msg-Id: evt-2\r\n\r\n"
+		fmt.Fprintf(conn, "MSG happen.events.order.created 1 5\r\nfirst\r\n")
+		fmt.Fprintf(conn, "MSG happen.events.order.created 1 _INBOX.reply 6\r\nsecond\r\n")
+		fmt.Fprintf(conn, "HMSG happen.events.order.shipped 1 %d %d\r\n%sthird\r\n", len(headers), len(headers)+5, headers)
+		fmt.Fprintf(conn, "HMSG happen.events.order.shipped 1 _INBOX.reply %d %d\r\n%s\r\n", len(headers), len(headers), headers)
+		io.WriteString(conn, "PING\r\n")
+		pong <- readLine()
+		readLine() // hold the connection open until the client closes it
+	}()
+
+	nc, err := dialNATS("nats://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer nc.close()
+
+	if options := <-connect; options["headers"] != true || options["name"] != tuiNodeID {
+		t.Errorf("CONNECT = %v, want headers on and the TUI's node name", options)
+	}
+
+	deliveries := make(chan natsDelivery, 4)
+	if _, err := nc.subscribe("happen.events.>", collect(deliveries)); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []natsDelivery{
+		{"happen.events.order.created", "", "first"},
+		{"happen.events.order.created", "_INBOX.reply", "second"},
+		{"happen.events.order.shipped", "", "third"},
+		{"happen.events.order.shipped", "_INBOX.reply", ""},
+	} {
+		if got := nextDelivery(t, deliveries); got != want {
+			t.Errorf("delivered %+v, want %+v", got, want)
+		}
+	}
+
+	select {
+	case line := <-pong:
+		if line != "PONG" {
+			t.Errorf("answered PING with %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("PING not answered")
+	}
+}
+
+func TestNATSHandshakeError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, `INFO {"auth_required":true}`+"\r\n")
+		bufio.NewReader(conn).ReadString('\n')
+		io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+	}()
+
+	if _, err := dialNATS("nats://" + ln.Addr().String()); err == nil || !strings.Contains(err.Error(), "Authorization Violation") {
+		t.Errorf("dial error = %v, want the server's -ERR", err)
+	}
+}
+
+func TestNATSDeliverRejectsBadSizes(t *testing.T) {
+	for _, tc := range []struct {
+		args    string
+		headers bool
+	}{
+		{"happen.events.x 1 -1", false},
+		{"happen.events.x 1 _INBOX.reply -5", false},
+		{"happen.events.x 1 -3 4", true},
+		{"happen.events.x 1 2 -1", true},
+		{"happen.events.x 1 6 4", true},
+		{"happen.events.x 1 many", false},
+	} {
+		nc := &natsConn{reader: bufio.NewReader(strings.NewReader("body\r\n")), subs: map[int]func(string, string, []byte){}}
+		if err := nc.deliver(tc.args, tc.headers); err == nil {
+			t.Errorf("deliver(%q, headers=%v) succeeded, want an error", tc.args, tc.headers)
+		}
+	}
+}
+
+// startNATSServer runs a local nats-server, the stand-in for a Happen mesh
+func startNATSServer(t *testing.T) string {
+	t.Helper()
+	bin, err := exec.LookPath("nats-server")
+	if err != nil {
+		t.Skip("nats-server not installed")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().(*net.TCPAddr)
+	ln.Close()
+
+	cmd := exec.Command(bin, "-a", "127.0.0.1", "-p", fmt.Sprint(addr.Port))
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if conn, err := net.Dial("tcp", addr.String()); err == nil {
+			conn.Close()
+			return "nats://" + addr.String()
+		}
+	}
+	t.Fatal("nats-server did not start")
+	return ""
+}
+
+func TestNATSServerRoundTrip(t *testing.T) {
+	url := startNATSServer(t)
+
+	nc, err := dialNATS(url)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer nc.close()
+
+	deliveries := make(chan natsDelivery, 1)
+	if _, err := nc.subscribe("cabal.test", collect(deliveries)); err != nil {
+		t.Fatal(err)
+	}
+	// Answer requests on another subject
+	if _, err := nc.subscribe("cabal.echo", func(_, reply string, data []byte) {
+		nc.publish(reply, "", append([]byte("echo "), data...))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := nc.publish("cabal.test", "", []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if got := nextDelivery(t, deliveries); got.subject != "cabal.test" || got.data != "hello" {
+		t.Errorf("delivered %+v", got)
+	}
+
+	reply, err := nc.request("cabal.echo", []byte("ping"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if string(reply) != "echo ping" {
+		t.Errorf("reply = %q", reply)
+	}
+}
+
+func TestNATSClientMapsHappenEvents(t *testing.T) {
+	url := startNATSServer(t)
+
+	client, err := NewNATSClient(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	publisher, err := dialNATS(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer publisher.close()
+
+	data, err := encodeMsgpack(map[string]interface{}{
+		"id":      "evt-1",
+		"type":    "order.created",
+		"payload": map[string]interface{}{"orderId": "o-1"},
+		"context": map[string]interface{}{
+			"causal":    map[string]interface{}{"id": "evt-1", "sender": "orders", "path": []string{"orders"}},
+			"timestamp": 1717171717171,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Give the server a moment to register the client's subscriptions
+	time.Sleep(200 * time.Millisecond)
+	if err := publisher.publish("happen.events.node.billing.order.created", "", data); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]WSMessage)
+	for timeout := time.After(5 * time.Second); len(seen) < 3; {
+		select {
+		case frame := <-client.Receive():
+			seen[frame.Type] = frame
+		case <-timeout:
+			t.Fatalf("frames received: %v", seen)
+		}
+	}
+	event := seen["event"].Payload.(map[string]interface{})
+	if event["type"] != "order.created" || event["from"] != "orders" || event["to"] != "billing" {
+		t.Errorf("event frame = %v", event)
+	}
+	agents := seen["registry:update"].Payload.(map[string]interface{})["agents"].([]map[string]interface{})
+	if len(agents) != 1 || agents[0]["id"] != "orders" {
+		t.Errorf("registry = %v, want the sender", agents)
+	}
+}
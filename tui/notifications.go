@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const defaultSnooze = 15 * time.Minute
+
+// notification is one agent:notification kept for the notification center
+type notification struct {
+	agentID      string
+	level        agentStatus
+	at           time.Time
+	message      string
+	pending      int
+	acknowledged bool
+	snoozed      bool // arrived while the agent was snoozed
+}
+
+func (l agentStatus) String() string {
+	switch l {
+	case statusCritical:
+		return "critical"
+	case statusNotification:
+		return "notification"
+	}
+	return "normal"
+}
+
+func levelStyle(level agentStatus) lipgloss.Style {
+	switch level {
+	case statusCritical:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	case statusNotification:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	}
+	return statusStyle
+}
+
+// maxNotifications bounds the notification center; the oldest are dropped first
+const maxNotifications = 500
+
+// notificationCenter holds recent notifications and per-agent snoozes
+type notificationCenter struct {
+	items        []*notification // newest first
+	snoozedUntil map[string]time.Time
+	cursor       int
+	open         bool
+	prompt       textinput.Model
+	prompting    bool
+}
+
+func newNotificationCenter() notificationCenter {
+	prompt := textinput.New()
+	prompt.Placeholder = strconv.Itoa(int(defaultSnooze.Minutes()))
+	prompt.CharLimit = 4
+	prompt.Width = 6
+	return notificationCenter{snoozedUntil: make(map[string]time.Time), prompt: prompt}
+}
+
+func (c *notificationCenter) snoozed(agentID string, now time.Time) bool {
+	return now.Before(c.snoozedUntil[agentID])
+}
+
+// level is the highest unacknowledged level for an agent, ignoring it while snoozed
+func (c *notificationCenter) level(agentID string, now time.Time) agentStatus {
+	if c.snoozed(agentID, now) {
+		return statusNormal
+	}
+	level := statusNormal
+	for _, n := range c.items {
+		if n.agentID == agentID && !n.acknowledged && n.level > level {
+			level = n.level
+		}
+	}
+	return level
+}
+
+func (c *notificationCenter) unacknowledged() int {
+	count := 0
+	for _, n := range c.items {
+		if !n.acknowledged && n.level != statusNormal {
+			count++
+		}
+	}
+	return count
+}
+
+// add records a notification. A normal-level notification means the bridge has cleared the
+// agent, so it acknowledges everything that agent raised before.
+func (c *notificationCenter) add(n *notification) {
+	if n.level == statusNormal {
+		n.acknowledged = true
+		for _, earlier := range c.items {
+			if earlier.agentID == n.agentID {
+				earlier.acknowledged = true
+			}
+		}
+	}
+	c.items = append([]*notification{n}, c.items...)
+	if len(c.items) > maxNotifications {
+		c.items = c.items[:maxNotifications]
+	}
+	if c.cursor > 0 {
+		c.cursor = min(c.cursor+1, len(c.items)-1)
+	}
+}
+
+// syncNotificationLevels derives each agent's border colour from the notification center
+func (m *model) syncNotificationLevels() {
+	now := time.Now()
+	for i := range m.agents {
+		m.agents[i].notificationLevel = m.notifications.level(m.agents[i].id, now)
+	}
+	m.refreshAgentList()
+}
+
+func (m *model) refreshAgentList() {
+	items := make([]list.Item, len(m.agents))
+	for i, a := range m.agents {
+		items[i] = a
+	}
+	m.agentList.SetItems(items)
+}
+
+func (m *model) agentDisplayName(id string) string {
+	for _, a := range m.agents {
+		if a.id == id {
+			return a.name
+		}
+	}
+	return id
+}
+
+type snoozeEndedMsg struct {
+	agentID string
+}
+
+// snooze silences an agent's notifications for d and schedules their return
+func (m *model) snooze(agentID string, d time.Duration) tea.Cmd {
+	m.notifications.snoozedUntil[agentID] = time.Now().Add(d)
+	m.syncNotificationLevels()
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return snoozeEndedMsg{agentID: agentID}
+	})
+}
+
+// handleNotificationKey drives the notification center while it is open
+func (m *model) handleNotificationKey(msg tea.KeyMsg) tea.Cmd {
+	c := &m.notifications
+	if c.prompting {
+		switch msg.String() {
+		case "esc":
+			c.prompting = false
+			c.prompt.Blur()
+		case "enter":
+			c.prompting = false
+			c.prompt.Blur()
+			minutes, err := strconv.Atoi(strings.TrimSpace(c.prompt.Value()))
+			if c.prompt.Value() == "" {
+				minutes, err = int(defaultSnooze.Minutes()), nil
+			}
+			if err == nil && minutes > 0 && c.cursor < len(c.items) {
+				return m.snooze(c.items[c.cursor].agentID, time.Duration(minutes)*time.Minute)
+			}
+		default:
+			var cmd tea.Cmd
+			c.prompt, cmd = c.prompt.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "esc", "ctrl+n":
+		c.open = false
+	case "up", "k":
+		c.cursor = max(0, c.cursor-1)
+	case "down", "j":
+		c.cursor = min(max(0, len(c.items)-1), c.cursor+1)
+	case "a", "enter":
+		if c.cursor < len(c.items) {
+			c.items[c.cursor].acknowledged = true
+		}
+	case "A":
+		for _, n := range c.items {
+			n.acknowledged = true
+		}
+	case "s":
+		if c.cursor < len(c.items) {
+			c.prompt.SetValue("")
+			c.prompting = true
+			return c.prompt.Focus()
+		}
+	case "S":
+		if c.cursor < len(c.items) {
+			delete(c.snoozedUntil, c.items[c.cursor].agentID)
+		}
+	}
+	m.syncNotificationLevels()
+	return nil
+}
+
+func (m *model) renderNotificationCenter(width, height int) string {
+	c := &m.notifications
+	now := time.Now()
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s %s\n\n",
+		agentStyle.Render("📬 Notifications"),
+		statusStyle.Render(fmt.Sprintf("%d unacknowledged of %d", c.unacknowledged(), len(c.items))),
+	))
+
+	var snoozes []string
+	for id, until := range c.snoozedUntil {
+		if now.Before(until) {
+			snoozes = append(snoozes, fmt.Sprintf("%s until %s", m.agentDisplayName(id), until.Format("15:04")))
+		}
+	}
+	if len(snoozes) > 0 {
+		content.WriteString(statusStyle.Render("💤 Snoozed: "+strings.Join(snoozes, ", ")) + "\n\n")
+	}
+
+	if len(c.items) == 0 {
+		content.WriteString(statusStyle.Render("No notifications yet") + "\n")
+	}
+	rows := max(1, height-8)
+	start := max(0, min(c.cursor-rows+1, len(c.items)-rows))
+	for i := start; i < min(len(c.items), start+rows); i++ {
+		n := c.items[i]
+		cursor := "  "
+		if i == c.cursor {
+			cursor = "▸ "
+		}
+		mark := "  "
+		switch {
+		case n.acknowledged:
+			mark = "✓ "
+		case n.snoozed:
+			mark = "💤"
+		}
+		line := fmt.Sprintf("%s %-14s %-12s %s",
+			n.at.Format("15:04:05"),
+			truncate(m.agentDisplayName(n.agentID), 14),
+			n.level,
+			n.message,
+		)
+		style := levelStyle(n.level)
+		if n.acknowledged {
+			style = statusStyle
+		}
+		content.WriteString(cursor + mark + " " + style.Render(truncate(line, width-6)) + "\n")
+	}
+
+	if status := m.notifier.status(); status != "" {
+		content.WriteString("\n" + statusStyle.Render(truncate(status, width)) + "\n")
+	}
+	if c.prompting {
+		content.WriteString("\n" + fmt.Sprintf("Snooze %s for minutes: %s", m.agentDisplayName(c.items[c.cursor].agentID), c.prompt.View()))
+	}
+	content.WriteString("\n" + statusStyle.Render("↑/↓: select • a: ack • A: ack all • s: snooze agent • S: unsnooze • Esc: close"))
+	return content.String()
+}
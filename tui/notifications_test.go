@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSnoozeKeepsConversation(t *testing.T) {
+	m := initialModel()
+	m.snooze("agent-1", time.Hour)
+	next, _ := m.Update(notificationMsg{agentId: "agent-1", notificationLevel: statusCritical, message: "approve deploy?"})
+	m = next.(model)
+
+	agent := m.agents[1]
+	if last := agent.messages[len(agent.messages)-1]; last.content != "📢 approve deploy?" {
+		t.Errorf("last message = %q, want the snoozed notification", last.content)
+	}
+	if agent.notificationLevel != statusNormal {
+		t.Errorf("border level = %v while snoozed", agent.notificationLevel)
+	}
+
+	delete(m.notifications.snoozedUntil, "agent-1")
+	m.syncNotificationLevels()
+	if m.agents[1].notificationLevel != statusCritical {
+		t.Errorf("border level = %v after the snooze, want critical", m.agents[1].notificationLevel)
+	}
+}
+
+func TestNotificationCenterCap(t *testing.T) {
+	c := newNotificationCenter()
+	c.cursor = 3
+	for i := 0; i < maxNotifications+10; i++ {
+		c.add(&notification{agentID: "agent-0", level: statusNotification, message: fmt.Sprint(i)})
+	}
+	if len(c.items) != maxNotifications {
+		t.Fatalf("%d notifications kept, want %d", len(c.items), maxNotifications)
+	}
+	if c.items[0].message != fmt.Sprint(maxNotifications+9) {
+		t.Errorf("newest = %q", c.items[0].message)
+	}
+	if c.cursor >= len(c.items) {
+		t.Errorf("cursor %d past %d items", c.cursor, len(c.items))
+	}
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Sink kinds
+const (
+	sinkBell    = "bell"
+	sinkOSC9    = "osc9"
+	sinkOSC777  = "osc777"
+	sinkExec    = "exec"
+	sinkWebhook = "webhook"
+	sinkSMTP    = "smtp"
+)
+
+const sinkTimeout = 10 * time.Second
+
+// NotifySink forwards agent notifications outside the terminal. Levels defaults to
+// critical only; RateLimit is the minimum gap between two deliveries and QuietHours
+// ("22:00-07:00", local time) suppresses the sink entirely.
+type NotifySink struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Levels     []string `json:"levels"`
+	RateLimit  string   `json:"rateLimit"`
+	QuietHours string   `json:"quietHours"`
+
+	// exec
+	Command []string `json:"command"`
+
+	// webhook
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+
+	// smtp; the password is read from PasswordEnv so it never sits in the config file
+	Addr        string   `json:"addr"`
+	From        string   `json:"from"`
+	To          []string `json:"to"`
+	Username    string   `json:"username"`
+	PasswordEnv string   `json:"passwordEnv"`
+
+	interval   time.Duration
+	quietFrom  time.Duration // offset from midnight
+	quietTo    time.Duration
+	quiet      bool
+	lastSent   time.Time
+	suppressed int
+}
+
+// LoadNotifySinks reads a JSON array of sinks; an empty path yields none
+func LoadNotifySinks(path string) ([]*NotifySink, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sinks []*NotifySink
+	if err := json.Unmarshal(data, &sinks); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, sink := range sinks {
+		if err := sink.parse(); err != nil {
+			return nil, fmt.Errorf("notification sink %q: %w", sink.Name, err)
+		}
+	}
+	return sinks, nil
+}
+
+func (s *NotifySink) parse() error {
+	if s.Name == "" {
+		s.Name = s.Kind
+	}
+	switch s.Kind {
+	case sinkBell, sinkOSC9, sinkOSC777:
+	case sinkExec:
+		if len(s.Command) == 0 {
+			return fmt.Errorf("exec sink needs a command")
+		}
+	case sinkWebhook:
+		if s.URL == "" {
+			return fmt.Errorf("webhook sink needs a url")
+		}
+	case sinkSMTP:
+		if s.Addr == "" || s.From == "" || len(s.To) == 0 {
+			return fmt.Errorf("smtp sink needs addr, from and to")
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", s.Kind)
+	}
+
+	if len(s.Levels) == 0 {
+		s.Levels = []string{statusCritical.String()}
+	}
+	for _, level := range s.Levels {
+		if level != statusCritical.String() && level != statusNotification.String() && level != statusNormal.String() {
+			return fmt.Errorf("unknown level %q", level)
+		}
+	}
+
+	if s.RateLimit != "" {
+		d, err := time.ParseDuration(s.RateLimit)
+		if err != nil {
+			return fmt.Errorf("rateLimit: %w", err)
+		}
+		s.interval = d
+	}
+
+	if s.QuietHours != "" {
+		from, to, ok := strings.Cut(s.QuietHours, "-")
+		var err error
+		if s.quietFrom, err = parseClock(from); err == nil && ok {
+			s.quietTo, err = parseClock(to)
+		}
+		if !ok || err != nil {
+			return fmt.Errorf("quietHours %q must look like \"22:00-07:00\"", s.QuietHours)
+		}
+		s.quiet = true
+	}
+	return nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// inQuietHours handles ranges that wrap past midnight
+func (s *NotifySink) inQuietHours(now time.Time) bool {
+	if !s.quiet {
+		return false
+	}
+	clock := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
+	if s.quietFrom <= s.quietTo {
+		return clock >= s.quietFrom && clock < s.quietTo
+	}
+	return clock >= s.quietFrom || clock < s.quietTo
+}
+
+// admit decides whether this notification goes out now, counting what the rate limit holds back
+func (s *NotifySink) admit(level agentStatus, now time.Time) bool {
+	if !containsString(s.Levels, level.String()) || s.inQuietHours(now) {
+		return false
+	}
+	if s.interval > 0 && now.Sub(s.lastSent) < s.interval {
+		s.suppressed++
+		return false
+	}
+	s.lastSent = now
+	return true
+}
+
+// sinkPayload is what exec hooks receive on stdin and webhooks receive as the body
+type sinkPayload struct {
+	AgentID         string `json:"agentId"`
+	Agent           string `json:"agent"`
+	Level           string `json:"level"`
+	Message         string `json:"message"`
+	PendingRequests int    `json:"pendingRequests"`
+	Timestamp       int64  `json:"timestamp"`
+	Suppressed      int    `json:"suppressed"` // held back by the rate limit since the last delivery
+}
+
+func (p sinkPayload) summary() string {
+	text := fmt.Sprintf("%s [%s]: %s", p.Agent, p.Level, p.Message)
+	if p.Suppressed > 0 {
+		text += fmt.Sprintf(" (+%d more)", p.Suppressed)
+	}
+	return text
+}
+
+// terminalOutput is the program's output. Bubble Tea renders frames through it and sinks
+// write bell and OSC sequences through it, so a sequence never lands inside a frame.
+var terminalOutput io.Writer = newSharedTerminal(os.Stdout)
+
+// sharedTerminal serialises writes to the terminal. It wraps the file rather than embedding it,
+// so no promoted method such as WriteString can write around the lock, and exposes just what
+// Bubble Tea needs to recognise a TTY (term.File: Fd, Read, Write and Close).
+type sharedTerminal struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+func newSharedTerminal(f *os.File) *sharedTerminal {
+	return &sharedTerminal{file: f}
+}
+
+func (t *sharedTerminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.file.Write(p)
+}
+
+func (t *sharedTerminal) Read(p []byte) (int, error) { return t.file.Read(p) }
+func (t *sharedTerminal) Fd() uintptr                { return t.file.Fd() }
+func (t *sharedTerminal) Close() error               { return t.file.Close() }
+
+// stripControl keeps a message from terminating an escape sequence early
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+func (s *NotifySink) deliver(p sinkPayload) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sinkTimeout)
+	defer cancel()
+
+	switch s.Kind {
+	case sinkBell:
+		_, err := io.WriteString(terminalOutput, "\a")
+		return err
+	case sinkOSC9:
+		_, err := fmt.Fprintf(terminalOutput, "\x1b]9;%s\x07", stripControl(p.summary()))
+		return err
+	case sinkOSC777:
+		_, err := fmt.Fprintf(terminalOutput, "\x1b]777;notify;%s;%s\x07",
+			stripControl("CABAL: "+p.Agent), stripControl(strings.ReplaceAll(p.summary(), ";", ",")))
+		return err
+
+	case sinkExec:
+		data, _ := json.Marshal(p)
+		cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+		cmd.Stdin = bytes.NewReader(data)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v: %s", err, truncate(strings.TrimSpace(string(out)), 80))
+		}
+		return nil
+
+	case sinkWebhook:
+		data, _ := json.Marshal(p)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range s.Headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+		return nil
+
+	case sinkSMTP:
+		var auth smtp.Auth
+		if s.Username != "" {
+			host, _, _ := strings.Cut(s.Addr, ":")
+			auth = smtp.PlainAuth("", s.Username, os.Getenv(s.PasswordEnv), host)
+		}
+		body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: [CABAL %s] %s\r\n\r\n%s\r\n\r\nPending requests: %d\r\nTime: %s\r\n",
+			s.From, strings.Join(s.To, ", "), p.Level, stripControl(p.Agent), stripControl(p.summary()), p.PendingRequests,
+			time.UnixMilli(p.Timestamp).Format(time.RFC1123))
+		return smtp.SendMail(s.Addr, auth, s.From, s.To, []byte(body))
+	}
+	return fmt.Errorf("unknown kind %q", s.Kind)
+}
+
+// notifyDispatcher fans notifications out to the configured sinks
+type notifyDispatcher struct {
+	sinks  []*NotifySink
+	errors map[string]string // last failure per sink, cleared on success
+}
+
+func newNotifyDispatcher(sinks []*NotifySink) *notifyDispatcher {
+	return &notifyDispatcher{sinks: sinks, errors: make(map[string]string)}
+}
+
+// sinkResultMsg reports one delivery back to the model
+type sinkResultMsg struct {
+	sink string
+	err  error
+}
+
+// dispatch picks the sinks that admit this notification and delivers to each in the background
+func (d *notifyDispatcher) dispatch(agentID, agentName string, level agentStatus, message string, pending int, now time.Time) tea.Cmd {
+	if d == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, sink := range d.sinks {
+		if !sink.admit(level, now) {
+			continue
+		}
+		sink := sink
+		payload := sinkPayload{
+			AgentID:         agentID,
+			Agent:           agentName,
+			Level:           level.String(),
+			Message:         message,
+			PendingRequests: pending,
+			Timestamp:       now.UnixMilli(),
+			Suppressed:      sink.suppressed,
+		}
+		sink.suppressed = 0
+		cmds = append(cmds, func() tea.Msg {
+			return sinkResultMsg{sink: sink.Name, err: sink.deliver(payload)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (d *notifyDispatcher) record(msg sinkResultMsg) {
+	if msg.err != nil {
+		d.errors[msg.sink] = msg.err.Error()
+	} else {
+		delete(d.errors, msg.sink)
+	}
+}
+
+// status summarises sink health in one line
+func (d *notifyDispatcher) status() string {
+	if d == nil || len(d.sinks) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(d.sinks))
+	for _, sink := range d.sinks {
+		if err, ok := d.errors[sink.Name]; ok {
+			parts = append(parts, fmt.Sprintf("%s ✗ %s", sink.Name, err))
+		} else {
+			parts = append(parts, sink.Name+" ✓")
+		}
+	}
+	return "Sinks: " + strings.Join(parts, " • ")
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parsedSink(t *testing.T, sink NotifySink) *NotifySink {
+	t.Helper()
+	if err := sink.parse(); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	return &sink
+}
+
+func clock(hour, minute int) time.Time {
+	return time.Date(2024, 3, 1, hour, minute, 0, 0, time.Local)
+}
+
+func TestSinkAdmit(t *testing.T) {
+	type step struct {
+		at    time.Time
+		level agentStatus
+		want  bool
+	}
+	cases := []struct {
+		name           string
+		sink           NotifySink
+		steps          []step
+		wantSuppressed int
+	}{
+		{
+			name: "critical only by default",
+			sink: NotifySink{Kind: sinkBell},
+			steps: []step{
+				{clock(12, 0), statusCritical, true},
+				{clock(12, 1), statusNotification, false},
+				{clock(12, 2), statusNormal, false},
+			},
+		},
+		{
+			name: "listed levels",
+			sink: NotifySink{Kind: sinkBell, Levels: []string{"notification", "normal"}},
+			steps: []step{
+				{clock(12, 0), statusCritical, false},
+				{clock(12, 1), statusNotification, true},
+				{clock(12, 2), statusNormal, true},
+			},
+		},
+		{
+			name: "rate limit holds back and counts",
+			sink: NotifySink{Kind: sinkBell, RateLimit: "5m"},
+			steps: []step{
+				{clock(12, 0), statusCritical, true},
+				{clock(12, 1), statusCritical, false},
+				{clock(12, 4), statusCritical, false},
+				{clock(12, 5), statusCritical, true},
+			},
+			wantSuppressed: 2,
+		},
+		{
+			name: "filtered levels are not counted as suppressed",
+			sink: NotifySink{Kind: sinkBell, RateLimit: "5m"},
+			steps: []step{
+				{clock(12, 0), statusCritical, true},
+				{clock(12, 1), statusNormal, false},
+			},
+		},
+		{
+			name: "quiet hours spanning midnight",
+			sink: NotifySink{Kind: sinkBell, QuietHours: "22:00-07:00"},
+			steps: []step{
+				{clock(21, 59), statusCritical, true},
+				{clock(22, 0), statusCritical, false},
+				{clock(23, 30), statusCritical, false},
+				{clock(0, 0), statusCritical, false},
+				{clock(6, 59), statusCritical, false},
+				{clock(7, 0), statusCritical, true},
+			},
+		},
+		{
+			name: "quiet hours within a day",
+			sink: NotifySink{Kind: sinkBell, QuietHours: "12:00-13:30"},
+			steps: []step{
+				{clock(11, 59), statusCritical, true},
+				{clock(12, 0), statusCritical, false},
+				{clock(13, 29), statusCritical, false},
+				{clock(13, 30), statusCritical, true},
+				{clock(23, 0), statusCritical, true},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := parsedSink(t, tc.sink)
+			for _, s := range tc.steps {
+				if got := sink.admit(s.level, s.at); got != s.want {
+					t.Errorf("admit(%s, %s) = %v, want %v", s.level, s.at.Format("15:04"), got, s.want)
+				}
+			}
+			if sink.suppressed != tc.wantSuppressed {
+				t.Errorf("suppressed = %d, want %d", sink.suppressed, tc.wantSuppressed)
+			}
+		})
+	}
+}
+
+func TestSinkParseErrors(t *testing.T) {
+	for _, sink := range []NotifySink{
+		{Kind: "pager"},
+		{Kind: sinkExec},
+		{Kind: sinkWebhook},
+		{Kind: sinkSMTP, Addr: "localhost:25"},
+		{Kind: sinkBell, Levels: []string{"loud"}},
+		{Kind: sinkBell, RateLimit: "often"},
+		{Kind: sinkBell, QuietHours: "22:00"},
+		{Kind: sinkBell, QuietHours: "late-early"},
+	} {
+		if err := sink.parse(); err == nil {
+			t.Errorf("parse(%+v) succeeded, want an error", sink)
+		}
+	}
+}
+
+var testPayload = sinkPayload{
+	AgentID:         "agent-1",
+	Agent:           "coder",
+	Level:           "critical",
+	Message:         "needs approval",
+	PendingRequests: 2,
+	Timestamp:       1700000000000,
+	Suppressed:      3,
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got sinkPayload
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	sink := parsedSink(t, NotifySink{Kind: sinkWebhook, URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}})
+	if err := sink.deliver(testPayload); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if got != testPayload {
+		t.Errorf("body = %+v, want %+v", got, testPayload)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestWebhookSinkStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	sink := parsedSink(t, NotifySink{Kind: sinkWebhook, URL: server.URL})
+	if err := sink.deliver(testPayload); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("deliver error = %v, want the 502 status", err)
+	}
+}
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecSink(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "payload.json")
+	script := writeScript(t, "cat > \"$1\"\n")
+
+	sink := parsedSink(t, NotifySink{Kind: sinkExec, Command: []string{"sh", script, out}})
+	if err := sink.deliver(testPayload); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got sinkPayload
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("hook stdin is not JSON: %v", err)
+	}
+	if got != testPayload {
+		t.Errorf("stdin = %+v, want %+v", got, testPayload)
+	}
+}
+
+func TestExecSinkFailure(t *testing.T) {
+	script := writeScript(t, "echo 'no route to pager' >&2\nexit 3\n")
+
+	sink := parsedSink(t, NotifySink{Kind: sinkExec, Command: []string{"sh", script}})
+	err := sink.deliver(testPayload)
+	if err == nil || !strings.Contains(err.Error(), "no route to pager") {
+		t.Errorf("deliver error = %v, want the hook's output", err)
+	}
+}
+
+// fakeSMTP accepts one message and returns what was sent after DATA
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var body strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					body.WriteString(line)
+				}
+				messages <- body.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), messages
+}
+
+func TestSMTPSink(t *testing.T) {
+	addr, messages := fakeSMTP(t)
+
+	sink := parsedSink(t, NotifySink{Kind: sinkSMTP, Addr: addr, From: "cabal@example.com", To: []string{"ops@example.com"}})
+	if err := sink.deliver(testPayload); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	select {
+	case message := <-messages:
+		for _, want := range []string{
+			"To: ops@example.com",
+			"Subject: [CABAL critical] coder",
+			"coder [critical]: needs approval (+3 more)",
+			"Pending requests: 2",
+		} {
+			if !strings.Contains(message, want) {
+				t.Errorf("message missing %q:\n%s", want, message)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestTerminalSinks(t *testing.T) {
+	defer func(w io.Writer) { terminalOutput = w }(terminalOutput)
+	var buf bytes.Buffer
+	terminalOutput = &buf
+
+	payload := testPayload
+	payload.Message = "line one\nline two\x07"
+	for _, tc := range []struct {
+		kind string
+		want string
+	}{
+		{sinkBell, "\a"},
+		{sinkOSC9, "\x1b]9;coder [critical]: line one line two  (+3 more)\x07"},
+		{sinkOSC777, "\x1b]777;notify;CABAL: coder;coder [critical]: line one line two  (+3 more)\x07"},
+	} {
+		buf.Reset()
+		sink := parsedSink(t, NotifySink{Kind: tc.kind})
+		if err := sink.deliver(payload); err != nil {
+			t.Fatalf("%s: %v", tc.kind, err)
+		}
+		if buf.String() != tc.want {
+			t.Errorf("%s wrote %q, want %q", tc.kind, buf.String(), tc.want)
+		}
+	}
+}
+
+func TestSharedTerminalWritesThroughLock(t *testing.T) {
+	var out interface{} = newSharedTerminal(os.Stdout)
+	// io.WriteString and io.Copy would use these instead of the locked Write
+	if _, ok := out.(io.StringWriter); ok {
+		t.Error("sharedTerminal has a WriteString that bypasses the lock")
+	}
+	if _, ok := out.(io.ReaderFrom); ok {
+		t.Error("sharedTerminal has a ReadFrom that bypasses the lock")
+	}
+	// Bubble Tea only treats term.File outputs as a TTY
+	if _, ok := out.(interface {
+		io.ReadWriteCloser
+		Fd() uintptr
+	}); !ok {
+		t.Error("sharedTerminal is not a term.File")
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Autonomy levels understood by the human-in-the-loop coordinator
+var autonomyLevels = []string{"full", "supervised", "manual"}
+
+// The coordinator asks for a human review below this decision confidence
+const reviewConfidence = 0.8
+
+// Recorded decisions kept for policy previews
+const maxDecisions = 500
+
+// AgentPolicy mirrors the coordinator's per-agent policy
+type AgentPolicy struct {
+	AgentID             string   `json:"agentId"`
+	AutonomyLevel       string   `json:"autonomyLevel"`
+	RequiresApprovalFor []string `json:"requiresApprovalFor"`
+	NotifyHumanFor      []string `json:"notifyHumanFor"`
+}
+
+func defaultPolicy(agentID string) AgentPolicy {
+	return AgentPolicy{AgentID: agentID, AutonomyLevel: "supervised"}
+}
+
+// AgentDecision is a decision an agent reported to the coordinator
+type AgentDecision struct {
+	AgentID      string
+	DecisionType string
+	Decision     string
+	Confidence   float64
+	At           time.Time
+}
+
+// Outcomes of a decision under a policy
+const (
+	outcomeAutonomous = "autonomous"
+	outcomeReview     = "review"
+	outcomeApproval   = "approval"
+)
+
+// outcome applies the coordinator's handleDecision: listed decision types need approval and any
+// other decision below the confidence threshold is flagged for review. The coordinator does not
+// consult the autonomy level, which only the agent itself acts on.
+func (p AgentPolicy) outcome(d AgentDecision) string {
+	switch {
+	case containsString(p.RequiresApprovalFor, d.DecisionType):
+		return outcomeApproval
+	case d.Confidence < reviewConfidence:
+		return outcomeReview
+	}
+	return outcomeAutonomous
+}
+
+// notifies reports whether a message would be surfaced to a human under notifyHumanFor
+func (p AgentPolicy) notifies(message string) bool {
+	for _, pattern := range p.NotifyHumanFor {
+		if pattern != "" && strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyEdit records one change pushed from the control center
+type policyEdit struct {
+	At     time.Time
+	Before AgentPolicy
+	After  AgentPolicy
+}
+
+func (e policyEdit) summary() string {
+	var changes []string
+	if e.Before.AutonomyLevel != e.After.AutonomyLevel {
+		changes = append(changes, fmt.Sprintf("autonomy %s → %s", e.Before.AutonomyLevel, e.After.AutonomyLevel))
+	}
+	if strings.Join(e.Before.RequiresApprovalFor, ",") != strings.Join(e.After.RequiresApprovalFor, ",") {
+		changes = append(changes, fmt.Sprintf("approval [%s]", strings.Join(e.After.RequiresApprovalFor, ", ")))
+	}
+	if strings.Join(e.Before.NotifyHumanFor, ",") != strings.Join(e.After.NotifyHumanFor, ",") {
+		changes = append(changes, fmt.Sprintf("notify [%s]", strings.Join(e.After.NotifyHumanFor, ", ")))
+	}
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	return strings.Join(changes, "; ")
+}
+
+// Policy form fields
+const (
+	policyFieldApproval = iota
+	policyFieldNotify
+	policyFieldCount
+)
+
+// policyForm edits one agent's policy
+type policyForm struct {
+	agent  AgentInfo
+	level  int
+	inputs [policyFieldCount]textinput.Model
+	focus  int // policyFieldCount selects the autonomy level
+	err    string
+	// pending is set from the push until the bridge echoes the policy back or rejects it
+	pending bool
+}
+
+func newPolicyForm(agent AgentInfo, policy AgentPolicy) *policyForm {
+	form := &policyForm{agent: agent, focus: policyFieldCount}
+	for i, level := range autonomyLevels {
+		if level == policy.AutonomyLevel {
+			form.level = i
+		}
+	}
+	prompts := [policyFieldCount]string{"Approval for:  ", "Notify for:    "}
+	values := [policyFieldCount][]string{policy.RequiresApprovalFor, policy.NotifyHumanFor}
+	for i := range form.inputs {
+		input := textinput.New()
+		input.Prompt = prompts[i]
+		input.Placeholder = "comma separated"
+		input.Width = 60
+		input.SetValue(strings.Join(values[i], ", "))
+		form.inputs[i] = input
+	}
+	return form
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (f *policyForm) policy() AgentPolicy {
+	return AgentPolicy{
+		AgentID:             f.agent.ID,
+		AutonomyLevel:       autonomyLevels[f.level],
+		RequiresApprovalFor: splitList(f.inputs[policyFieldApproval].Value()),
+		NotifyHumanFor:      splitList(f.inputs[policyFieldNotify].Value()),
+	}
+}
+
+func (f *policyForm) focusField(field int) {
+	if f.focus < policyFieldCount {
+		f.inputs[f.focus].Blur()
+	}
+	f.focus = (field + policyFieldCount + 1) % (policyFieldCount + 1)
+	if f.focus < policyFieldCount {
+		f.inputs[f.focus].Focus()
+	}
+}
+
+func (m *controlCenterModel) policyFor(agentID string) AgentPolicy {
+	if policy, ok := m.policies[agentID]; ok {
+		return policy
+	}
+	return defaultPolicy(agentID)
+}
+
+func (m *controlCenterModel) recordDecision(decision AgentDecision) {
+	m.decisions = append(m.decisions, decision)
+	if len(m.decisions) > maxDecisions {
+		m.decisions = m.decisions[len(m.decisions)-maxDecisions:]
+	}
+}
+
+func (m *controlCenterModel) openPolicyForm(agent AgentInfo) {
+	m.policyForm = newPolicyForm(agent, m.policyFor(agent.ID))
+}
+
+func (m *controlCenterModel) handlePolicyFormKey(msg tea.KeyMsg) tea.Cmd {
+	form := m.policyForm
+	switch msg.String() {
+	case "esc":
+		m.policyForm = nil
+	case "tab", "down":
+		form.focusField(form.focus + 1)
+	case "shift+tab", "up":
+		form.focusField(form.focus - 1)
+	case "enter":
+		m.pushPolicy()
+	case "left", "right", " ":
+		if form.focus == policyFieldCount {
+			step := 1
+			if msg.String() == "left" {
+				step = len(autonomyLevels) - 1
+			}
+			form.level = (form.level + step) % len(autonomyLevels)
+			return nil
+		}
+		fallthrough
+	default:
+		if form.focus < policyFieldCount {
+			var cmd tea.Cmd
+			form.inputs[form.focus], cmd = form.inputs[form.focus].Update(msg)
+			return cmd
+		}
+	}
+	return nil
+}
+
+// pushPolicy sends the edited policy to the bridge; the edit is recorded once the bridge echoes it
+func (m *controlCenterModel) pushPolicy() {
+	form := m.policyForm
+	if form.pending {
+		return
+	}
+	before, after := m.policyFor(form.agent.ID), form.policy()
+	if m.wsClient == nil {
+		form.err = "not connected to the bridge"
+		return
+	}
+	if err := m.wsClient.Send("agent:policy", after); err != nil {
+		form.err = fmt.Sprintf("push failed: %v", err)
+		return
+	}
+	m.pendingPolicies[after.AgentID] = policyEdit{Before: before, After: after}
+	form.err, form.pending = "", true
+}
+
+// handlePolicyUpdate applies a policy from the bridge. The echo of a pushed edit records the
+// edit as the bridge applied it and closes its form; a rejection goes back to the form.
+func (m *controlCenterModel) handlePolicyUpdate(msg AgentPolicyUpdate) {
+	agentID := msg.Policy.AgentID
+	pending, waiting := m.pendingPolicies[agentID]
+	delete(m.pendingPolicies, agentID)
+	form := m.policyForm
+	if form != nil && form.agent.ID != agentID {
+		form = nil
+	}
+
+	if msg.Err != "" {
+		if form != nil && form.pending {
+			form.pending = false
+			form.err = "rejected by the bridge: " + msg.Err
+		}
+		return
+	}
+	m.policies[agentID] = msg.Policy
+	if !waiting {
+		return
+	}
+
+	now := time.Now()
+	edit := policyEdit{At: now, Before: pending.Before, After: msg.Policy}
+	m.policyEdits[agentID] = append(m.policyEdits[agentID], edit)
+	m.addEvent(EventInfo{
+		Timestamp: now.Format("15:04:05"),
+		At:        now,
+		Type:      "policy:updated",
+		From:      "control-center",
+		To:        m.agentName(agentID),
+		Message:   edit.summary(),
+	})
+	if form != nil && form.pending {
+		m.policyForm = nil
+	}
+}
+
+func (m *controlCenterModel) renderPolicyForm(width int) string {
+	form := m.policyForm
+	current, proposed := m.policyFor(form.agent.ID), form.policy()
+
+	var content strings.Builder
+	levels := make([]string, len(autonomyLevels))
+	for i, level := range autonomyLevels {
+		levels[i] = level
+		if i == form.level {
+			levels[i] = statValueStyle.Render("[" + level + "]")
+		}
+	}
+	marker := "  "
+	if form.focus == policyFieldCount {
+		marker = "▸ "
+	}
+	content.WriteString(fmt.Sprintf("%sAutonomy:      %s\n", marker, strings.Join(levels, "  ")))
+	for _, input := range form.inputs {
+		content.WriteString("  " + input.View() + "\n")
+	}
+	if form.err != "" {
+		content.WriteString(offlineStyle.Render(form.err) + "\n")
+	}
+	if form.pending {
+		content.WriteString(busyStyle.Render("Waiting for the bridge to apply the policy…") + "\n")
+	}
+
+	// Preview past decisions under the current and proposed policies
+	var decisions []AgentDecision
+	for _, d := range m.decisions {
+		if d.AgentID == form.agent.ID {
+			decisions = append(decisions, d)
+		}
+	}
+	countApprovals := func(p AgentPolicy) int {
+		n := 0
+		for _, d := range decisions {
+			if p.outcome(d) == outcomeApproval {
+				n++
+			}
+		}
+		return n
+	}
+	content.WriteString(fmt.Sprintf("\n%s %d of %d recorded decisions would need approval (currently %d)\n",
+		statLabelStyle.Render("Preview:"), countApprovals(proposed), len(decisions), countApprovals(current)))
+	content.WriteString(statusStyle.Render("  as the coordinator decides; the autonomy level does not change its outcome") + "\n")
+
+	shown := 0
+	for i := len(decisions) - 1; i >= 0 && shown < 8; i-- {
+		d := decisions[i]
+		was, now := current.outcome(d), proposed.outcome(d)
+		change := stateStyle(now).Render(now)
+		if was != now {
+			change = fmt.Sprintf("%s → %s", was, busyStyle.Render(now))
+		}
+		line := fmt.Sprintf("  %s %-16s %.2f  %s", d.At.Format("15:04:05"), truncate(d.DecisionType, 16), d.Confidence, change)
+		content.WriteString(truncate(line, width) + "\n")
+		shown++
+	}
+
+	notified := 0
+	for _, event := range m.eventLog.events {
+		if event.From == form.agent.Name && proposed.notifies(event.Message) {
+			notified++
+		}
+	}
+	if len(proposed.NotifyHumanFor) > 0 {
+		content.WriteString(fmt.Sprintf("  %d recorded messages from %s match the notify keywords\n", notified, form.agent.Name))
+	}
+
+	if edits := m.policyEdits[form.agent.ID]; len(edits) > 0 {
+		content.WriteString("\n" + statLabelStyle.Render("Edit history") + "\n")
+		for i := len(edits) - 1; i >= max(0, len(edits)-5); i-- {
+			line := fmt.Sprintf("  %s %s", edits[i].At.Format("2006-01-02 15:04:05"), edits[i].summary())
+			content.WriteString(truncate(line, width) + "\n")
+		}
+	}
+
+	content.WriteString("\n" + statusStyle.Render("↑/↓: field • ←/→: autonomy • Enter: push to bridge • Esc: cancel"))
+	return content.String()
+}
+
+// AgentPolicyUpdate is the bridge's view of an agent's current policy
+type AgentPolicyUpdate struct {
+	Policy AgentPolicy
+	Err    string // set when the bridge rejected a pushed policy
+}
+
+// AgentDecisionUpdate is a decision reported through the coordinator
+type AgentDecisionUpdate struct {
+	Decision AgentDecision
+}
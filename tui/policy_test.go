@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestPolicyEditRecordedOnEcho(t *testing.T) {
+	m := initialControlCenterModel()
+	transport := &recordingTransport{}
+	m.wsClient = transport
+	agent := AgentInfo{ID: "agent-7", Name: "researcher"}
+	m.agents = []AgentInfo{agent}
+
+	m.openPolicyForm(agent)
+	m.policyForm.level = 0
+	m.pushPolicy()
+	if len(transport.sent) != 1 || !m.policyForm.pending {
+		t.Fatalf("sent %v, pending %v", transport.sent, m.policyForm.pending)
+	}
+	if len(m.policyEdits[agent.ID]) != 0 {
+		t.Fatal("edit recorded before the bridge applied it")
+	}
+
+	// The bridge normalises what it applies; the echo is what gets recorded
+	applied := AgentPolicy{AgentID: agent.ID, AutonomyLevel: autonomyLevels[0], RequiresApprovalFor: []string{"deploy"}}
+	next, _ := m.Update(decodeBridgeMessage(WSMessage{Type: "agent:policy", Payload: applied}))
+	m = next.(controlCenterModel)
+	if m.policyForm != nil {
+		t.Error("form still open after the echo")
+	}
+	edits := m.policyEdits[agent.ID]
+	if len(edits) != 1 || edits[0].After.AutonomyLevel != applied.AutonomyLevel || len(edits[0].After.RequiresApprovalFor) != 1 {
+		t.Fatalf("edits = %+v", edits)
+	}
+	if len(m.events) == 0 || m.events[0].Type != "policy:updated" || m.events[0].To != "researcher" {
+		t.Errorf("events = %+v", m.events)
+	}
+
+	// A policy broadcast nobody here pushed updates the view without an edit
+	next, _ = m.Update(AgentPolicyUpdate{Policy: AgentPolicy{AgentID: agent.ID, AutonomyLevel: autonomyLevels[1]}})
+	m = next.(controlCenterModel)
+	if len(m.policyEdits[agent.ID]) != 1 || m.policyFor(agent.ID).AutonomyLevel != autonomyLevels[1] {
+		t.Errorf("edits %d, policy %+v", len(m.policyEdits[agent.ID]), m.policyFor(agent.ID))
+	}
+}
+
+func TestPolicyRejected(t *testing.T) {
+	m := initialControlCenterModel()
+	m.wsClient = &recordingTransport{}
+	agent := AgentInfo{ID: "agent-9", Name: "ghost"}
+	m.openPolicyForm(agent)
+	m.pushPolicy()
+
+	next, _ := m.Update(decodeBridgeMessage(WSMessage{Type: "agent:policy", Payload: map[string]interface{}{"agentId": "agent-9", "error": "unknown agent agent-9"}}))
+	m = next.(controlCenterModel)
+	if m.policyForm == nil || m.policyForm.pending || m.policyForm.err == "" {
+		t.Fatalf("form = %+v, want it open with the rejection", m.policyForm)
+	}
+	if len(m.policyEdits[agent.ID]) != 0 || len(m.pendingPolicies) != 0 {
+		t.Error("rejected edit was recorded")
+	}
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	happenStream     = "HAPPEN_EVENTS" // created by Happen's NATS transport
+	maxReplayFrames  = 20000
+	replayIdle       = 3 * time.Second // give up when the consumer goes quiet this long
+	replayTickPeriod = 200 * time.Millisecond
+)
+
+var replaySpeeds = []float64{1, 10, 60, 600}
+
+// replayFrame is one decoded message from the stream, stamped with its stream sequence and time
+type replayFrame struct {
+	seq uint64
+	at  time.Time
+	msg tea.Msg
+}
+
+// recording is a stretch of JetStream history, decoded like live bridge frames
+type recording struct {
+	stream    string
+	frames    []replayFrame
+	truncated bool // more history followed than maxReplayFrames
+}
+
+// replayStart is where a recording begins: a stream sequence, or failing that a time
+type replayStart struct {
+	seq uint64
+	at  time.Time
+}
+
+// parseReplayStart accepts a sequence ("1200" or "#1200"), a look-back ("8h"), a clock time
+// today ("02:00"), a local date and time ("2026-10-18 02:00") or RFC 3339; empty means the start
+func parseReplayStart(s string, now time.Time) (replayStart, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return replayStart{seq: 1}, nil
+	}
+	if seq, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 10, 64); err == nil {
+		return replayStart{seq: max(1, seq)}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return replayStart{at: now.Add(-d)}, nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if at.After(now) {
+			at = at.AddDate(0, 0, -1)
+		}
+		return replayStart{at: at}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return replayStart{at: t}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return replayStart{at: t}, nil
+	}
+	return replayStart{}, fmt.Errorf("start %q: use a sequence, a duration like 8h, 15:04, 2006-01-02 15:04 or RFC 3339", s)
+}
+
+// jsAck reads stream sequence, timestamp and pending count from a push consumer's reply
+// subject: $JS.ACK.<stream>.<consumer>.<delivered>.<sseq>.<cseq>.<ts>.<pending>, or the
+// newer form with domain and account hash in front and a random token at the end
+func jsAck(reply string) (seq uint64, at time.Time, pending uint64, ok bool) {
+	tokens := strings.Split(reply, ".")
+	n := len(tokens)
+	var offset int
+	switch {
+	case n == 9 && tokens[0] == "$JS" && tokens[1] == "ACK":
+		offset = 0
+	case n >= 12 && tokens[0] == "$JS" && tokens[1] == "ACK":
+		offset = 2
+	default:
+		return 0, time.Time{}, 0, false
+	}
+	seq, err1 := strconv.ParseUint(tokens[offset+5], 10, 64)
+	ns, err2 := strconv.ParseInt(tokens[offset+7], 10, 64)
+	pending, err3 := strconv.ParseUint(tokens[offset+8], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, time.Time{}, 0, false
+	}
+	return seq, time.Unix(0, ns), pending, true
+}
+
+// jsError is the error block of a JetStream API response
+type jsError struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+func jsRequest(nc *natsConn, subject string, req interface{}, resp interface{}) error {
+	var data []byte
+	if req != nil {
+		data, _ = json.Marshal(req)
+	}
+	reply, err := nc.request(subject, data, natsTimeout)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Error *jsError `json:"error"`
+	}
+	if err := json.Unmarshal(reply, &result); err != nil {
+		return fmt.Errorf("%s: %w", subject, err)
+	}
+	if result.Error != nil {
+		return fmt.Errorf("jetstream: %s (%d)", result.Error.Description, result.Error.Code)
+	}
+	if resp != nil {
+		return json.Unmarshal(reply, resp)
+	}
+	return nil
+}
+
+// fetchRecording pulls history from Happen's stream from the given start
+func (c *NATSClient) fetchRecording(start replayStart) (*recording, error) {
+	var info struct {
+		State struct {
+			Messages uint64 `json:"messages"`
+			LastSeq  uint64 `json:"last_seq"`
+		} `json:"state"`
+	}
+	if err := jsRequest(c.nc, "$JS.API.STREAM.INFO."+happenStream, nil, &info); err != nil {
+		return nil, err
+	}
+	rec := &recording{stream: happenStream}
+	if info.State.Messages == 0 || start.seq > info.State.LastSeq {
+		return rec, nil
+	}
+
+	config := map[string]interface{}{}
+	if start.seq > 0 {
+		config["deliver_policy"] = "by_start_sequence"
+		config["opt_start_seq"] = start.seq
+	} else {
+		config["deliver_policy"] = "by_start_time"
+		config["opt_start_time"] = start.at.UTC().Format(time.RFC3339Nano)
+	}
+	received, truncated, err := c.readStream(happenStream, config, maxReplayFrames)
+	if err != nil {
+		return nil, err
+	}
+	rec.truncated = truncated
+
+	mapper := newHappenMapper()
+	for _, r := range received {
+		body, err := decodeHappenPayload(r.data)
+		if err != nil {
+			continue
+		}
+		for _, frame := range mapper.frames(r.subject, body, r.at) {
+			if msg := decodeBridgeMessage(frame); msg != nil {
+				rec.frames = append(rec.frames, replayFrame{seq: r.seq, at: r.at, msg: msg})
+			}
+		}
+	}
+	return rec, nil
+}
+
+// streamMessage is one message read back from a JetStream stream
+type streamMessage struct {
+	subject string
+	data    []byte
+	seq     uint64
+	at      time.Time
+}
+
+// readStream reads a stream through an ephemeral push consumer without acks, stopping when
+// nothing is pending, limit messages have arrived (reported as truncated) or the stream goes
+// quiet. config adds to the consumer configuration, e.g. a deliver policy or filter subject.
+func (c *NATSClient) readStream(stream string, config map[string]interface{}, limit int) ([]streamMessage, bool, error) {
+	var (
+		mu        sync.Mutex
+		received  []streamMessage
+		truncated bool
+		last      = time.Now()
+		finished  = make(chan struct{})
+		once      sync.Once
+	)
+	inbox := newInbox()
+	sid, err := c.nc.subscribe(inbox, func(subject, reply string, data []byte) {
+		seq, at, pending, ok := jsAck(reply)
+		if !ok {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		last = time.Now()
+		if len(received) >= limit {
+			truncated = true
+			once.Do(func() { close(finished) })
+			return
+		}
+		received = append(received, streamMessage{subject: subject, data: data, seq: seq, at: at})
+		if pending == 0 {
+			once.Do(func() { close(finished) })
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	defer c.nc.unsubscribe(sid)
+
+	consumerConfig := map[string]interface{}{
+		"deliver_subject":    inbox,
+		"deliver_policy":     "all",
+		"ack_policy":         "none",
+		"replay_policy":      "instant",
+		"inactive_threshold": int64(time.Minute),
+	}
+	for k, v := range config {
+		consumerConfig[k] = v
+	}
+	var consumer struct {
+		Name string `json:"name"`
+	}
+	if err := jsRequest(c.nc, "$JS.API.CONSUMER.CREATE."+stream,
+		map[string]interface{}{"stream_name": stream, "config": consumerConfig}, &consumer); err != nil {
+		return nil, false, err
+	}
+	defer jsRequest(c.nc, "$JS.API.CONSUMER.DELETE."+stream+"."+consumer.Name, nil, nil)
+
+	ticker := time.NewTicker(replayIdle / 3)
+	defer ticker.Stop()
+wait:
+	for {
+		select {
+		case <-finished:
+			break wait
+		case <-c.nc.done:
+			return nil, false, fmt.Errorf("nats connection closed: %v", c.nc.err)
+		case <-ticker.C:
+			mu.Lock()
+			idle := time.Since(last) > replayIdle
+			mu.Unlock()
+			if idle {
+				break wait
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return received, truncated, nil
+}
+
+// replayState scrubs through a recording; the live events and history wait aside meanwhile
+type replayState struct {
+	rec     *recording
+	pos     int // frames applied
+	clock   time.Time
+	playing bool
+	speed   int // index into replaySpeeds
+
+	liveEvents  []EventInfo
+	liveHistory *metricsHistory
+}
+
+type replayLoadedMsg struct {
+	rec *recording
+	err error
+}
+
+type replayTickMsg time.Time
+
+func replayTick() tea.Cmd {
+	return tea.Tick(replayTickPeriod, func(t time.Time) tea.Msg { return replayTickMsg(t) })
+}
+
+// openReplayPrompt asks where to start; replay needs the NATS transport
+func (m *controlCenterModel) openReplayPrompt() {
+	if _, ok := m.wsClient.(*NATSClient); !ok {
+		m.replayNotice = "Replay reads JetStream history: start with -bridge nats://…"
+		return
+	}
+	m.replayNotice = ""
+	m.promptingReplay = true
+	m.replayPrompt.SetValue("")
+	m.replayPrompt.Focus()
+}
+
+func (m *controlCenterModel) loadReplay(input string) tea.Cmd {
+	start, err := parseReplayStart(input, time.Now())
+	if err != nil {
+		m.replayNotice = err.Error()
+		return nil
+	}
+	client := m.wsClient.(*NATSClient)
+	m.replayNotice = fmt.Sprintf("Loading %s history…", happenStream)
+	return func() tea.Msg {
+		rec, err := client.fetchRecording(start)
+		return replayLoadedMsg{rec: rec, err: err}
+	}
+}
+
+func (m *controlCenterModel) handleReplayLoaded(msg replayLoadedMsg) {
+	switch {
+	case msg.err != nil:
+		m.replayNotice = "Replay failed: " + msg.err.Error()
+		return
+	case len(msg.rec.frames) == 0:
+		m.replayNotice = "No history in " + msg.rec.stream + " from there"
+		return
+	}
+	m.replayNotice = ""
+	if m.replay == nil {
+		m.replay = &replayState{liveEvents: m.events, liveHistory: m.history}
+	}
+	m.replay.rec = msg.rec
+	m.replay.playing = false
+	m.replay.pos = -1 // force a rebuild
+	m.seekReplay(1)
+}
+
+func (m *controlCenterModel) exitReplay() {
+	m.events = m.replay.liveEvents
+	m.history = m.replay.liveHistory
+	m.replay = nil
+	m.updateEventView()
+	m.updateAnalyticsView()
+}
+
+// seekReplay shows the recording with its first pos frames applied, replaying forwards from
+// the current position or rebuilding from the start
+func (m *controlCenterModel) seekReplay(pos int) {
+	r := m.replay
+	frames := r.rec.frames
+	pos = max(0, min(len(frames), pos))
+	if r.pos < 0 || pos < r.pos {
+		m.events = nil
+		m.history = newMetricsHistory()
+		r.pos = 0
+	}
+	for ; r.pos < pos; r.pos++ {
+		m.applyReplayFrame(frames[r.pos])
+	}
+	r.clock = frames[max(0, pos-1)].at
+	m.updateEventView()
+	m.updateAnalyticsView()
+}
+
+// seekReplayTime moves to the last frame at or before t
+func (m *controlCenterModel) seekReplayTime(t time.Time) {
+	frames := m.replay.rec.frames
+	pos := sort.Search(len(frames), func(i int) bool { return frames[i].at.After(t) })
+	m.seekReplay(pos)
+	m.replay.clock = t
+}
+
+func (m *controlCenterModel) applyReplayFrame(f replayFrame) {
+	switch msg := f.msg.(type) {
+	case EventStreamUpdate:
+		event := msg.Event
+		event.Integrity, event.IntegrityNote = m.integrity.check(msg.Raw)
+		m.events = append([]EventInfo{event}, m.events...)
+		if len(m.events) > 100 {
+			m.events = m.events[:100]
+		}
+	case SystemStatsUpdate:
+		m.history.recordStats(f.at, msg.Stats)
+	case AgentRegistryUpdate:
+		m.history.recordAgents(f.at, msg.Agents)
+	}
+}
+
+// liveHistory is where live metrics go, set aside while a replay is showing
+func (m *controlCenterModel) liveHistory() *metricsHistory {
+	if m.replay != nil {
+		return m.replay.liveHistory
+	}
+	return m.history
+}
+
+// clock is the time the Analytics charts end at: now, or the replay position
+func (m *controlCenterModel) clock() time.Time {
+	if m.replay != nil {
+		return m.replay.clock
+	}
+	return time.Now()
+}
+
+func (m *controlCenterModel) handleReplayTick() tea.Cmd {
+	r := m.replay
+	if r == nil || !r.playing {
+		return nil
+	}
+	m.seekReplayTime(r.clock.Add(time.Duration(float64(replayTickPeriod) * replaySpeeds[r.speed])))
+	if r.pos >= len(r.rec.frames) {
+		r.playing = false
+		return nil
+	}
+	return replayTick()
+}
+
+// handleReplayKey drives the scrubber; it reports whether the key was used
+func (m *controlCenterModel) handleReplayKey(key string) (bool, tea.Cmd) {
+	r := m.replay
+	if r == nil {
+		return false, nil
+	}
+	frames := r.rec.frames
+	switch key {
+	case " ":
+		r.playing = !r.playing && r.pos < len(frames)
+		if r.playing {
+			return true, replayTick()
+		}
+	case "left", "h":
+		m.seekReplay(r.pos - 1)
+	case "right", "l":
+		m.seekReplay(r.pos + 1)
+	case "[":
+		m.seekReplayTime(r.clock.Add(-time.Minute))
+	case "]":
+		m.seekReplayTime(r.clock.Add(time.Minute))
+	case "{":
+		m.seekReplayTime(r.clock.Add(-10 * time.Minute))
+	case "}":
+		m.seekReplayTime(r.clock.Add(10 * time.Minute))
+	case "home", "g":
+		m.seekReplay(1)
+	case "end", "G":
+		m.seekReplay(len(frames))
+	case "+", "=":
+		r.speed = min(len(replaySpeeds)-1, r.speed+1)
+	case "-":
+		r.speed = max(0, r.speed-1)
+	case "esc":
+		m.exitReplay()
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// renderReplayBar shows where the replay is in the recording
+func (m *controlCenterModel) renderReplayBar(width int) string {
+	r := m.replay
+	frames := r.rec.frames
+	first, last := frames[0], frames[len(frames)-1]
+	state := "⏸"
+	if r.playing {
+		state = "▶"
+	}
+	seq := uint64(0)
+	if r.pos > 0 {
+		seq = frames[r.pos-1].seq
+	}
+	more := ""
+	if r.rec.truncated {
+		more = fmt.Sprintf(" (first %d)", maxReplayFrames)
+	}
+	head := fmt.Sprintf("⏺ Replay %s #%d–#%d%s • %s #%d • %d/%d • %s %gx",
+		r.rec.stream, first.seq, last.seq, more,
+		r.clock.Format("2006-01-02 15:04:05"), seq, r.pos, len(frames),
+		state, replaySpeeds[r.speed])
+
+	barWidth := max(10, width-22)
+	filled := 0
+	if span := last.at.Sub(first.at); span > 0 {
+		filled = int(float64(r.clock.Sub(first.at)) / float64(span) * float64(barWidth))
+	} else if r.pos == len(frames) {
+		filled = barWidth
+	}
+	filled = max(0, min(barWidth, filled))
+	bar := fmt.Sprintf("%s %s%s %s",
+		first.at.Format("15:04:05"),
+		busyStyle.Render(strings.Repeat("█", filled)),
+		statusStyle.Render(strings.Repeat("─", barWidth-filled)),
+		last.at.Format("15:04:05"))
+
+	help := statusStyle.Render("Space: play/pause • ←/→: step • [ ]: ±1m • { }: ±10m • g/G: start/end • +/-: speed • R: reload • Esc: live")
+	return lipgloss.JoinVertical(lipgloss.Left, busyStyle.Render(truncate(head, width)), bar, help)
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HumanRequest is a request from an agent that waits on a human response
+type HumanRequest struct {
+	ID       string
+	Type     string // approval, decision, input or review
+	Priority string // high, medium or low
+	From     string
+	Context  interface{}
+	Options  []string
+	At       time.Time
+	Timeout  time.Duration
+
+	// raw is the request as sent by the bridge, used by auto-approval selectors
+	raw map[string]interface{}
+
+	// autoRule and autoAt are set while an auto-response is waiting out its grace period
+	autoRule string
+	autoAt   time.Time
+}
+
+func priorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	}
+	return 0
+}
+
+func priorityStyle(priority string) lipgloss.Style {
+	switch priority {
+	case "high":
+		return offlineStyle
+	case "medium":
+		return busyStyle
+	}
+	return statusStyle
+}
+
+// contextSummary renders a request context on one line
+func (r *HumanRequest) contextSummary() string {
+	if r.Context == nil {
+		return ""
+	}
+	if s, ok := r.Context.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(r.Context)
+	if err != nil {
+		return fmt.Sprint(r.Context)
+	}
+	return string(data)
+}
+
+// addHumanRequest queues a request, sorted like the coordinator's pending list
+func (m *controlCenterModel) addHumanRequest(request *HumanRequest) {
+	m.humanRequests = append(m.humanRequests, request)
+	sort.SliceStable(m.humanRequests, func(i, j int) bool {
+		a, b := m.humanRequests[i], m.humanRequests[j]
+		if pa, pb := priorityRank(a.Priority), priorityRank(b.Priority); pa != pb {
+			return pa > pb
+		}
+		return a.At.Before(b.At)
+	})
+
+	m.addEvent(EventInfo{
+		Timestamp: request.At.Format("15:04:05"),
+		At:        request.At,
+		Type:      "human:request",
+		From:      m.agentLabel(request.From),
+		To:        "control-center",
+		Message:   fmt.Sprintf("%s (%s) %s", request.Type, request.Priority, request.contextSummary()),
+	})
+	m.scheduleAutoResponse(request)
+}
+
+func (m *controlCenterModel) findHumanRequest(id string) (int, *HumanRequest) {
+	for i, request := range m.humanRequests {
+		if request.ID == id {
+			return i, request
+		}
+	}
+	return -1, nil
+}
+
+func (m *controlCenterModel) removeHumanRequest(id string) {
+	if i, _ := m.findHumanRequest(id); i >= 0 {
+		m.humanRequests = append(m.humanRequests[:i], m.humanRequests[i+1:]...)
+	}
+	m.requestCursor = min(m.requestCursor, max(0, len(m.humanRequests)-1))
+}
+
+// expireHumanRequests drops requests the coordinator has already timed out
+func (m *controlCenterModel) expireHumanRequests(now time.Time) {
+	kept := m.humanRequests[:0]
+	for _, request := range m.humanRequests {
+		if request.Timeout > 0 && now.Sub(request.At) > request.Timeout {
+			m.recordSLA(request, now, true)
+			continue
+		}
+		kept = append(kept, request)
+	}
+	m.humanRequests = kept
+	m.requestCursor = min(m.requestCursor, max(0, len(m.humanRequests)-1))
+}
+
+// respond sends a human:response to the bridge and removes the request from the queue
+func (m *controlCenterModel) respond(request *HumanRequest, response interface{}, by string) error {
+	if m.wsClient == nil {
+		return fmt.Errorf("not connected to the bridge")
+	}
+	if err := m.wsClient.Send("human:response", map[string]interface{}{
+		"requestId": request.ID,
+		"response":  response,
+		"agentId":   request.From,
+	}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	data, _ := json.Marshal(response)
+	m.addEvent(EventInfo{
+		Timestamp: now.Format("15:04:05"),
+		At:        now,
+		Type:      "human:response",
+		From:      by,
+		To:        m.agentLabel(request.From),
+		Message:   fmt.Sprintf("%s %s", request.Type, data),
+	})
+	// Audit first: recordSLA forgets when the request arrived
+	m.recordAudit(request, string(data), by, now)
+	m.recordSLA(request, now, false)
+	m.removeHumanRequest(request.ID)
+	return nil
+}
+
+// agentLabel resolves an agent ID to its display name
+func (m *controlCenterModel) agentLabel(id string) string {
+	for _, agent := range m.agents {
+		if agent.ID == id {
+			return agent.Name
+		}
+	}
+	return id
+}
+
+func approvalResponse(approved bool) map[string]interface{} {
+	if approved {
+		return map[string]interface{}{"approved": true}
+	}
+	return map[string]interface{}{"approved": false, "reason": "rejected by operator"}
+}
+
+func (m *controlCenterModel) renderRequestsTab() string {
+	title := titleStyle.Render("🙋 Human Requests")
+	now := time.Now()
+	width := m.width - 8
+
+	if m.showAudit {
+		help := statusStyle.Render("/: filter • e: export CSV • L/Esc: back to requests")
+		return m.frameStyle().
+			Width(m.width - 4).
+			Height(m.height - 6).
+			Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("📜 Decision Audit Log"), "", m.renderAuditLog(width), help))
+	}
+
+	var content strings.Builder
+	content.WriteString(statLabelStyle.Render(fmt.Sprintf("Pending (%d)", len(m.humanRequests))) + "\n")
+	if len(m.humanRequests) == 0 {
+		content.WriteString(onlineStyle.Render("  Nothing waiting on you") + "\n")
+	}
+	for i, request := range m.humanRequests {
+		cursor := "  "
+		if i == m.requestCursor {
+			cursor = "▸ "
+		}
+		auto := ""
+		if request.autoRule != "" {
+			auto = busyStyle.Render(fmt.Sprintf(" auto in %ds (%s)", int(request.autoAt.Sub(now).Seconds())+1, request.autoRule))
+		}
+		line := fmt.Sprintf("%-8s %-9s %-14s %5s  %s",
+			request.Priority,
+			request.Type,
+			truncate(m.agentLabel(request.From), 14),
+			formatAge(m.sla.waited(request, now)),
+			request.contextSummary(),
+		)
+		style := priorityStyle(request.Priority)
+		if over := m.sla.breach(request, now); over > 0 {
+			auto = offlineStyle.Render(fmt.Sprintf(" ⚠ SLA +%s", formatAge(over))) + auto
+			style = style.Reverse(true)
+		}
+		content.WriteString(cursor + style.Render(truncate(line, width-lipgloss.Width(auto)-2)) + auto + "\n")
+	}
+
+	content.WriteString("\n" + m.renderAutoRules(width))
+
+	help := statusStyle.Render("↑/↓: select • y: approve • d: reject • r: auto-approve similar • R: new rule • u: undo auto • s: stop rule • L: audit log")
+	return m.frameStyle().
+		Width(m.width - 4).
+		Height(m.height - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", content.String(), help))
+}
+
+func (m *controlCenterModel) handleRequestKey(key string) {
+	m.requestError = ""
+	if m.showAudit {
+		m.handleAuditKey(key)
+		return
+	}
+	var selected *HumanRequest
+	if m.requestCursor < len(m.humanRequests) {
+		selected = m.humanRequests[m.requestCursor]
+	}
+
+	switch key {
+	case "up", "k":
+		m.requestCursor = max(0, m.requestCursor-1)
+	case "down", "j":
+		m.requestCursor = min(max(0, len(m.humanRequests)-1), m.requestCursor+1)
+	case "y", "d":
+		if selected == nil {
+			return
+		}
+		if err := m.respond(selected, approvalResponse(key == "y"), "operator"); err != nil {
+			m.requestError = err.Error()
+		}
+	case "r":
+		if selected != nil {
+			m.openRulePrompt(similarRule(selected, m.agentLabel(selected.From)))
+		}
+	case "R":
+		m.openRulePrompt("")
+	case "u":
+		m.undoAutoResponse(selected)
+	case "s":
+		m.stopAutoRule(selected)
+	case "L":
+		m.showAudit = true
+	}
+}
+
+// HumanRequestUpdate is forwarded from the coordinator's human:request event
+type HumanRequestUpdate struct {
+	Request *HumanRequest
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default response targets per request priority
+var defaultSLATargets = map[string]time.Duration{
+	"high":   30 * time.Second,
+	"medium": 2 * time.Minute,
+	"low":    10 * time.Minute,
+}
+
+const defaultSLATarget = 5 * time.Minute
+
+// parseSLATargets reads "2m" for a single target or "high=30s,medium=2m,low=10m,default=5m"
+func parseSLATargets(spec string) (map[string]time.Duration, time.Duration, error) {
+	targets := make(map[string]time.Duration, len(defaultSLATargets))
+	for priority, target := range defaultSLATargets {
+		targets[priority] = target
+	}
+	fallback := defaultSLATarget
+	if spec == "" {
+		return targets, fallback, nil
+	}
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d <= 0 {
+			return nil, 0, fmt.Errorf("invalid SLA target %q, want a positive duration", spec)
+		}
+		return map[string]time.Duration{}, d, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		priority, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		d, err := time.ParseDuration(value)
+		if !ok || err != nil || d <= 0 {
+			return nil, 0, fmt.Errorf("invalid SLA target %q, want priority=duration", part)
+		}
+		if priority == "default" {
+			fallback = d
+		} else {
+			targets[priority] = d
+		}
+	}
+	return targets, fallback, nil
+}
+
+// slaSample is one request that was answered or expired
+type slaSample struct {
+	At       time.Time
+	Agent    string
+	Type     string
+	Priority string
+	Wait     time.Duration
+	Expired  bool
+	Breached bool
+}
+
+// slaGroup aggregates samples for one agent, request type or priority
+type slaGroup struct {
+	waits    *latencySketch
+	answered int
+	expired  int
+	breached int
+}
+
+// slaTracker measures how long human requests wait for an answer
+type slaTracker struct {
+	targets  map[string]time.Duration
+	fallback time.Duration
+	samples  []slaSample
+	groups   map[[2]string]*slaGroup // {dimension, value}
+
+	// arrivals holds when each request's agent:notification was first seen, by request ID
+	arrivals map[string]time.Time
+}
+
+func newSLATracker(targets map[string]time.Duration, fallback time.Duration) *slaTracker {
+	return &slaTracker{
+		targets:  targets,
+		fallback: fallback,
+		groups:   make(map[[2]string]*slaGroup),
+		arrivals: make(map[string]time.Time),
+	}
+}
+
+// arrive starts the clock for a request when its notification comes in
+func (t *slaTracker) arrive(requestID string, now time.Time) {
+	if requestID == "" {
+		return
+	}
+	if _, ok := t.arrivals[requestID]; !ok {
+		t.arrivals[requestID] = now
+	}
+	for id, at := range t.arrivals {
+		if now.Sub(at) > historyRetention {
+			delete(t.arrivals, id)
+		}
+	}
+}
+
+// arrived is when a request started waiting: its notification, or the request itself if none was seen
+func (t *slaTracker) arrived(request *HumanRequest) time.Time {
+	if at, ok := t.arrivals[request.ID]; ok && at.Before(request.At) {
+		return at
+	}
+	return request.At
+}
+
+// waited is how long a request has waited by now; the SLA figures and the audit log both use it
+func (t *slaTracker) waited(request *HumanRequest, now time.Time) time.Duration {
+	return now.Sub(t.arrived(request))
+}
+
+func (t *slaTracker) target(priority string) time.Duration {
+	if target, ok := t.targets[priority]; ok {
+		return target
+	}
+	return t.fallback
+}
+
+// breach returns how far past its target a request has waited, or zero
+func (t *slaTracker) breach(request *HumanRequest, now time.Time) time.Duration {
+	return max(0, t.waited(request, now)-t.target(request.Priority))
+}
+
+func (t *slaTracker) record(sample slaSample) {
+	sample.Breached = sample.Expired || sample.Wait > t.target(sample.Priority)
+	t.samples = append(t.samples, sample)
+	t.samples = trimSamples(t.samples, sample.At, func(s slaSample) time.Time { return s.At })
+
+	for _, key := range [][2]string{{"Agent", sample.Agent}, {"Type", sample.Type}, {"Priority", sample.Priority}} {
+		group, ok := t.groups[key]
+		if !ok {
+			group = &slaGroup{waits: newLatencySketch()}
+			t.groups[key] = group
+		}
+		if sample.Expired {
+			group.expired++
+		} else {
+			group.answered++
+			group.waits.Add(float64(sample.Wait) / float64(time.Millisecond))
+		}
+		if sample.Breached {
+			group.breached++
+		}
+	}
+}
+
+func (m *controlCenterModel) recordSLA(request *HumanRequest, now time.Time, expired bool) {
+	wait := m.sla.waited(request, now)
+	delete(m.sla.arrivals, request.ID)
+	m.sla.record(slaSample{
+		At:       now,
+		Agent:    m.agentLabel(request.From),
+		Type:     request.Type,
+		Priority: request.Priority,
+		Wait:     wait,
+		Expired:  expired,
+	})
+}
+
+// renderSLA shows wait percentiles per agent, request type and priority with a trend of answered waits
+func (m *controlCenterModel) renderSLA(width int) string {
+	if len(m.sla.groups) == 0 {
+		return statusStyle.Render("No human requests answered or expired yet")
+	}
+
+	var content strings.Builder
+	window := chartWindows[m.chartWindow]
+	now := time.Now()
+	var times []time.Time
+	var waits []float64
+	for _, s := range m.sla.samples {
+		if !s.Expired {
+			times = append(times, s.At)
+			waits = append(waits, s.Wait.Seconds())
+		}
+	}
+	trendWidth := max(10, min(60, width-30))
+	trend := bucketSeries(times, waits, now, window.duration, trendWidth)
+	if latest, ok := lastValue(trend); ok {
+		content.WriteString(fmt.Sprintf("%s %s  %s  %s\n\n",
+			statLabelStyle.Render("Wait, last "+window.name),
+			busyStyle.Render(sparkline(trend)),
+			statValueStyle.Render(formatDuration(time.Duration(latest*float64(time.Second)))),
+			statusStyle.Render("(w: window)"),
+		))
+	}
+
+	header := fmt.Sprintf("%-9s %-16s %6s %8s %8s %7s %8s", "", "", "Done", "p50", "p95", "Expired", "Breached")
+	content.WriteString(statLabelStyle.Render(header) + "\n")
+	for _, dimension := range []string{"Priority", "Type", "Agent"} {
+		var values []string
+		for key := range m.sla.groups {
+			if key[0] == dimension {
+				values = append(values, key[1])
+			}
+		}
+		sort.Strings(values)
+		for i, value := range values {
+			group := m.sla.groups[[2]string{dimension, value}]
+			label := ""
+			if i == 0 {
+				label = dimension
+			}
+			p50, p95 := math.NaN(), math.NaN()
+			if group.answered > 0 {
+				p50, p95 = group.waits.Quantile(0.5), group.waits.Quantile(0.95)
+			}
+			line := fmt.Sprintf("%-9s %-16s %6d %8s %8s %7d %8d",
+				label, truncate(value, 16), group.answered, formatMillis(p50), formatMillis(p95), group.expired, group.breached)
+			if group.breached > 0 {
+				line = busyStyle.Render(line)
+			}
+			content.WriteString(line + "\n")
+		}
+	}
+
+	var targets []string
+	for _, priority := range []string{"high", "medium", "low"} {
+		targets = append(targets, fmt.Sprintf("%s %s", priority, formatAge(m.sla.target(priority))))
+	}
+	content.WriteString(statusStyle.Render("Targets: " + strings.Join(targets, " • ")))
+	return content.String()
+}
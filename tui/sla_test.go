@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSLATargets(t *testing.T) {
+	targets, fallback, err := parseSLATargets("high=30s,default=3m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if targets["high"] != 30*time.Second || targets["low"] != defaultSLATargets["low"] || fallback != 3*time.Minute {
+		t.Errorf("targets = %v, fallback %v", targets, fallback)
+	}
+	if _, fallback, err := parseSLATargets("90s"); err != nil || fallback != 90*time.Second {
+		t.Errorf("single target = %v, %v", fallback, err)
+	}
+	for _, spec := range []string{"0s", "-1m", "high=0s", "high=-5s", "high", "high=soon"} {
+		if _, _, err := parseSLATargets(spec); err == nil {
+			t.Errorf("parseSLATargets(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestAuditAndSLAAgreeOnWait(t *testing.T) {
+	m := initialControlCenterModel()
+	m.wsClient = &recordingTransport{}
+	now := time.Now()
+	// The notification arrived well before the request frame itself
+	request := &HumanRequest{ID: "req-1", Type: "approval", Priority: "high", From: "agent-1", At: now.Add(-10 * time.Second)}
+	m.sla.arrive(request.ID, now.Add(-40*time.Second))
+	m.humanRequests = []*HumanRequest{request}
+
+	if err := m.respond(request, "approve", "operator"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.audit.entries) != 1 || len(m.sla.samples) != 1 {
+		t.Fatalf("%d audit entries, %d SLA samples", len(m.audit.entries), len(m.sla.samples))
+	}
+	audited, measured := m.audit.entries[0].waited(), m.sla.samples[0].Wait
+	if audited != measured.Truncate(time.Millisecond) {
+		t.Errorf("audit says %v, SLA says %v", audited, measured)
+	}
+	if audited < 40*time.Second {
+		t.Errorf("waited %v, want it measured from the notification", audited)
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Happen records a StateSnapshot per handled event when a node has temporal state enabled
+// (temporal/index.ts). NatsTemporalStore keeps them as JSON in the KV bucket
+// happen_temporal_<nodeId>, keyed event:<eventId>; with the NATS transport the browser reads
+// that bucket's stream directly. Over the Node bridge it asks with a state:history frame
+// ({agentId}) and expects state:history back ({agentId, snapshots: [StateSnapshot]} or
+// {agentId, error}), which the Node bridge serves from the node's temporal store.
+
+const maxStateSnapshots = 5000
+
+// stateSnapshot is one StateSnapshot: the node's state right after handling an event
+type stateSnapshot struct {
+	EventID       string
+	EventType     string
+	At            time.Time
+	Sender        string
+	CorrelationID string
+	State         interface{}
+}
+
+// parseStateSnapshot reads a StateSnapshot's JSON form ({state, context: {eventType, ...}})
+func parseStateSnapshot(raw interface{}) (stateSnapshot, bool) {
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return stateSnapshot{}, false
+	}
+	context, ok := fields["context"].(map[string]interface{})
+	if !ok {
+		return stateSnapshot{}, false
+	}
+	if _, ok := fields["state"]; !ok {
+		return stateSnapshot{}, false
+	}
+	snapshot := stateSnapshot{State: fields["state"]}
+	snapshot.EventType, _ = context["eventType"].(string)
+	if ms, ok := context["timestamp"].(float64); ok {
+		snapshot.At = time.UnixMilli(int64(ms))
+	}
+	if causal, ok := context["causal"].(map[string]interface{}); ok {
+		snapshot.EventID, _ = causal["id"].(string)
+		snapshot.Sender, _ = causal["sender"].(string)
+		snapshot.CorrelationID, _ = causal["correlationId"].(string)
+	}
+	return snapshot, true
+}
+
+// sortSnapshots orders by timestamp, as the temporal store's queries do
+func sortSnapshots(snapshots []stateSnapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].At.Before(snapshots[j].At) })
+}
+
+// temporalBucket is NatsTemporalStore's bucket name for a node
+func temporalBucket(nodeID string) string {
+	return "happen_temporal_" + nodeID
+}
+
+// fetchStateHistory reads a node's snapshots from its KV bucket. The bucket's stream is KV_<bucket>
+// when JetStream created it, or the bare bucket name when the store created the stream itself.
+func (c *NATSClient) fetchStateHistory(nodeID string) ([]stateSnapshot, bool, error) {
+	bucket := temporalBucket(nodeID)
+	var stream string
+	var info struct {
+		State struct {
+			Messages uint64 `json:"messages"`
+			LastSeq  uint64 `json:"last_seq"`
+		} `json:"state"`
+	}
+	var err error
+	for _, name := range []string{"KV_" + bucket, bucket} {
+		if err = jsRequest(c.nc, "$JS.API.STREAM.INFO."+name, nil, &info); err == nil {
+			stream = name
+			break
+		}
+	}
+	if stream == "" {
+		return nil, false, fmt.Errorf("no temporal state for %s (%v)", nodeID, err)
+	}
+	if info.State.Messages == 0 {
+		return nil, false, nil
+	}
+
+	// Past the limit, read only the newest messages so the latest states are the ones shown
+	config := map[string]interface{}{"filter_subject": "$KV." + bucket + ".>"}
+	older := info.State.Messages > maxStateSnapshots && info.State.LastSeq > maxStateSnapshots
+	if older {
+		config["deliver_policy"] = "by_start_sequence"
+		config["opt_start_seq"] = info.State.LastSeq - maxStateSnapshots + 1
+	}
+	received, truncated, err := c.readStream(stream, config, maxStateSnapshots)
+	truncated = truncated || older
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The latest value per key wins; delete markers arrive with an empty body
+	latest := make(map[string]stateSnapshot)
+	for _, msg := range received {
+		key := strings.TrimPrefix(msg.subject, "$KV."+bucket+".")
+		if len(msg.data) == 0 {
+			delete(latest, key)
+			continue
+		}
+		var raw interface{}
+		if json.Unmarshal(msg.data, &raw) != nil {
+			continue
+		}
+		// Index keys (causal:, correlation:, type:) hold ID lists and are skipped here
+		if snapshot, ok := parseStateSnapshot(raw); ok {
+			if snapshot.EventID == "" {
+				snapshot.EventID = strings.TrimPrefix(key, "event:")
+			}
+			latest[key] = snapshot
+		}
+	}
+	snapshots := make([]stateSnapshot, 0, len(latest))
+	for _, snapshot := range latest {
+		snapshots = append(snapshots, snapshot)
+	}
+	sortSnapshots(snapshots)
+	return snapshots, truncated, nil
+}
+
+// StateHistoryUpdate carries a node's snapshots, from the bridge or the KV bucket
+type StateHistoryUpdate struct {
+	AgentID   string
+	Snapshots []stateSnapshot
+	Truncated bool
+	Err       error
+}
+
+// stateChange is one difference between two states, at a path such as cart.items[2].qty
+type stateChange struct {
+	path     string
+	kind     byte // '+' added, '-' removed, '~' changed
+	from, to interface{}
+}
+
+// diffState compares two JSON values structurally: objects by key, arrays by index
+func diffState(from, to interface{}) []stateChange {
+	var changes []stateChange
+	diffValue("", from, to, &changes)
+	return changes
+}
+
+func diffValue(path string, from, to interface{}, changes *[]stateChange) {
+	switch a := from.(type) {
+	case map[string]interface{}:
+		if b, ok := to.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(a)+len(b))
+			for k := range a {
+				keys = append(keys, k)
+			}
+			for k := range b {
+				if _, ok := a[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				av, inA := a[k]
+				bv, inB := b[k]
+				child := joinStatePath(path, k)
+				switch {
+				case !inA:
+					*changes = append(*changes, stateChange{path: child, kind: '+', to: bv})
+				case !inB:
+					*changes = append(*changes, stateChange{path: child, kind: '-', from: av})
+				default:
+					diffValue(child, av, bv, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := to.([]interface{}); ok {
+			for i := 0; i < max(len(a), len(b)); i++ {
+				child := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(a):
+					*changes = append(*changes, stateChange{path: child, kind: '+', to: b[i]})
+				case i >= len(b):
+					*changes = append(*changes, stateChange{path: child, kind: '-', from: a[i]})
+				default:
+					diffValue(child, a[i], b[i], changes)
+				}
+			}
+			return
+		}
+	}
+	if compactJSON(from) != compactJSON(to) {
+		if path == "" {
+			path = "(state)"
+		}
+		*changes = append(*changes, stateChange{path: path, kind: '~', from: from, to: to})
+	}
+}
+
+// joinStatePath appends an object key, quoting keys that are not plain identifiers
+func joinStatePath(path, key string) string {
+	plain := key != ""
+	for i, r := range key {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			plain = false
+			break
+		}
+	}
+	switch {
+	case !plain:
+		return path + "[" + strconv.Quote(key) + "]"
+	case path == "":
+		return key
+	}
+	return path + "." + key
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// State browser views
+const (
+	stateViewState = iota // the state after the selected event
+	stateViewDiff         // what the selected event changed, or the change since the mark
+)
+
+// stateHistoryTimeout bounds the wait for a bridge's state:history reply; a bridge that does
+// not serve state history never answers
+const stateHistoryTimeout = 10 * time.Second
+
+// stateBrowser steps through one agent's state history
+type stateBrowser struct {
+	agentID   string
+	agentName string
+	snapshots []stateSnapshot
+	truncated bool
+	loading   bool
+	request   int // counts loads so a timeout only ends the one it was started for
+	err       error
+	cursor    int
+	mark      int // snapshot to diff against, or -1 for the previous one
+	view      int
+	detail    viewport.Model
+}
+
+// openStateBrowser loads the selected agent's history from whichever transport is connected
+func (m *controlCenterModel) openStateBrowser(agent AgentInfo) tea.Cmd {
+	m.stateBrowser = &stateBrowser{
+		agentID:   agent.ID,
+		agentName: agent.Name,
+		loading:   true,
+		mark:      -1,
+		view:      stateViewDiff,
+		detail:    viewport.New(m.width-8, max(5, m.height-24)),
+	}
+	return m.loadStateHistory()
+}
+
+func (m *controlCenterModel) loadStateHistory() tea.Cmd {
+	b := m.stateBrowser
+	b.loading, b.err = true, nil
+	b.request++
+	switch c := m.wsClient.(type) {
+	case *NATSClient:
+		agentID := b.agentID
+		return func() tea.Msg {
+			snapshots, truncated, err := c.fetchStateHistory(agentID)
+			return StateHistoryUpdate{AgentID: agentID, Snapshots: snapshots, Truncated: truncated, Err: err}
+		}
+	case nil:
+		b.loading, b.err = false, fmt.Errorf("not connected to a bridge")
+	default:
+		if err := c.Send("state:history", map[string]interface{}{"agentId": b.agentID}); err != nil {
+			b.loading, b.err = false, err
+			return nil
+		}
+		agentID, request := b.agentID, b.request
+		return tea.Tick(stateHistoryTimeout, func(time.Time) tea.Msg {
+			return stateHistoryTimeoutMsg{agentID: agentID, request: request}
+		})
+	}
+	return nil
+}
+
+// stateHistoryTimeoutMsg ends a state:history request the bridge has not answered
+type stateHistoryTimeoutMsg struct {
+	agentID string
+	request int
+}
+
+func (m *controlCenterModel) handleStateHistoryTimeout(msg stateHistoryTimeoutMsg) {
+	b := m.stateBrowser
+	if b == nil || !b.loading || b.agentID != msg.agentID || b.request != msg.request {
+		return
+	}
+	b.loading = false
+	b.err = fmt.Errorf("the bridge did not answer state:history within %s; it may not serve state history", stateHistoryTimeout)
+}
+
+func (m *controlCenterModel) handleStateHistory(msg StateHistoryUpdate) {
+	b := m.stateBrowser
+	if b == nil || msg.AgentID != b.agentID {
+		return
+	}
+	b.loading, b.err, b.truncated = false, msg.Err, msg.Truncated
+	if msg.Err != nil {
+		return
+	}
+	b.snapshots = msg.Snapshots
+	b.cursor = max(0, len(b.snapshots)-1) // start at the latest state
+	b.mark = -1
+	m.updateStateDetail()
+}
+
+// handleStateKey drives the browser: ↑/↓ step through history, m marks a snapshot to diff against
+func (m *controlCenterModel) handleStateKey(msg tea.KeyMsg) tea.Cmd {
+	b := m.stateBrowser
+	last := len(b.snapshots) - 1
+	switch msg.String() {
+	case "esc", "backspace":
+		m.stateBrowser = nil
+		return nil
+	case "r":
+		return m.loadStateHistory()
+	case "up", "k", "left", "h":
+		b.cursor = max(0, b.cursor-1)
+	case "down", "j", "right", "l":
+		b.cursor = max(0, min(last, b.cursor+1))
+	case "home", "g":
+		b.cursor = 0
+	case "end", "G":
+		b.cursor = max(0, last)
+	case "enter", "d":
+		b.view = (b.view + 1) % 2
+	case "m":
+		if b.mark == b.cursor {
+			b.mark = -1
+		} else {
+			b.mark = b.cursor
+		}
+	default:
+		var cmd tea.Cmd
+		b.detail, cmd = b.detail.Update(msg)
+		return cmd
+	}
+	m.updateStateDetail()
+	b.detail.GotoTop()
+	return nil
+}
+
+func (m *controlCenterModel) updateStateDetail() {
+	b := m.stateBrowser
+	if len(b.snapshots) == 0 {
+		b.detail.SetContent("")
+		return
+	}
+	current := b.snapshots[b.cursor]
+	if b.view == stateViewState {
+		data, err := json.MarshalIndent(current.State, "", "  ")
+		if err != nil {
+			data = []byte(fmt.Sprint(current.State))
+		}
+		b.detail.SetContent(string(data))
+		return
+	}
+
+	base, label := b.mark, "mark"
+	if base < 0 {
+		base, label = b.cursor-1, "previous snapshot"
+	}
+	if base < 0 {
+		b.detail.SetContent(statusStyle.Render("First recorded state; nothing earlier to compare with"))
+		return
+	}
+	changes := diffState(b.snapshots[base].State, current.State)
+	var content strings.Builder
+	content.WriteString(statusStyle.Render(fmt.Sprintf("#%d → #%d (against the %s): %d change(s)",
+		base+1, b.cursor+1, label, len(changes))) + "\n\n")
+	if len(changes) == 0 {
+		content.WriteString(statusStyle.Render("State unchanged"))
+	}
+	width := max(20, b.detail.Width-4)
+	for _, c := range changes {
+		switch c.kind {
+		case '+':
+			content.WriteString(onlineStyle.Render(truncate("+ "+c.path+" = "+compactJSON(c.to), width)))
+		case '-':
+			content.WriteString(offlineStyle.Render(truncate("- "+c.path+" = "+compactJSON(c.from), width)))
+		default:
+			content.WriteString(busyStyle.Render(truncate("~ "+c.path+": "+compactJSON(c.from)+" → "+compactJSON(c.to), width)))
+		}
+		content.WriteString("\n")
+	}
+	b.detail.SetContent(content.String())
+}
+
+func (m *controlCenterModel) resizeStateBrowser() {
+	if b := m.stateBrowser; b != nil {
+		b.detail.Width = m.width - 8
+		b.detail.Height = max(5, m.height-24)
+	}
+}
+
+// renderStateBrowser lists snapshots around the cursor above the state or diff of the selected one
+func (m *controlCenterModel) renderStateBrowser() string {
+	b := m.stateBrowser
+	var list strings.Builder
+	switch {
+	case b.loading:
+		list.WriteString(statusStyle.Render("Loading snapshots…"))
+	case b.err != nil:
+		list.WriteString(offlineStyle.Render(b.err.Error()))
+	case len(b.snapshots) == 0:
+		list.WriteString(statusStyle.Render("No snapshots recorded; enable temporal state on the node"))
+	default:
+		const rows = 10
+		from := max(0, min(b.cursor-rows/2, len(b.snapshots)-rows))
+		for i := from; i < min(len(b.snapshots), from+rows); i++ {
+			s := b.snapshots[i]
+			cursor, mark := "  ", " "
+			if i == b.cursor {
+				cursor = "▸ "
+			}
+			if i == b.mark {
+				mark = busyStyle.Render("◆")
+			}
+			line := fmt.Sprintf("%s%s %4d  %s  %-28s %-16s %s",
+				cursor, mark, i+1,
+				s.At.Format("2006-01-02 15:04:05.000"),
+				truncate(s.EventType, 28),
+				truncate(s.Sender, 16),
+				truncate(s.EventID, 24),
+			)
+			if i == b.cursor {
+				line = statValueStyle.Render(line)
+			}
+			list.WriteString(line + "\n")
+		}
+		summary := fmt.Sprintf("%d snapshots", len(b.snapshots))
+		if b.truncated {
+			summary += fmt.Sprintf(" (from the latest %d messages only)", maxStateSnapshots)
+		}
+		list.WriteString(statusStyle.Render(summary))
+	}
+
+	heading := "State after this event"
+	if b.view == stateViewDiff {
+		heading = "Changes"
+	}
+	help := statusStyle.Render("↑/↓: step • g/G: first/last • d: state/diff • m: mark for diff • pgup/pgdn: scroll • r: reload • esc: back")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		list.String(),
+		"",
+		statLabelStyle.Render(heading),
+		b.detail.View(),
+		help,
+	)
+}
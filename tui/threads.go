@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Message kinds besides plain prompts and replies
+const (
+	kindPeer    = "peer"    // a peer consultation made on the thread's behalf
+	kindRequest = "request" // an approval or input request the thread triggered
+)
+
+// thread is a run of messages sharing a correlation ID, in arrival order
+type thread struct {
+	id       string // empty for a message that carried no correlation ID
+	messages []message
+}
+
+// groupThreads keeps threads in order of their first message; unthreaded messages stand alone
+func groupThreads(messages []message) []thread {
+	var threads []thread
+	index := make(map[string]int)
+	for _, msg := range messages {
+		if msg.correlationID == "" {
+			threads = append(threads, thread{messages: []message{msg}})
+			continue
+		}
+		if i, ok := index[msg.correlationID]; ok {
+			threads[i].messages = append(threads[i].messages, msg)
+			continue
+		}
+		index[msg.correlationID] = len(threads)
+		threads = append(threads, thread{id: msg.correlationID, messages: []message{msg}})
+	}
+	return threads
+}
+
+// title is the prompt that started the thread, or its first message
+func (t thread) title() string {
+	for _, msg := range t.messages {
+		if !msg.isAgent {
+			return msg.content
+		}
+	}
+	return t.messages[0].content
+}
+
+func (t thread) counts() (replies, peers, requests int) {
+	for _, msg := range t.messages {
+		switch {
+		case msg.kind == kindPeer:
+			peers++
+		case msg.kind == kindRequest:
+			requests++
+		case msg.isAgent:
+			replies++
+		}
+	}
+	return
+}
+
+func (t thread) header(collapsed, current bool, width int) string {
+	arrow := "▾"
+	if collapsed {
+		arrow = "▸"
+	}
+	replies, peers, requests := t.counts()
+	stats := fmt.Sprintf("%d %s", replies, plural(replies, "reply", "replies"))
+	if peers > 0 {
+		stats += fmt.Sprintf(" · %d %s", peers, plural(peers, "peer", "peers"))
+	}
+	if requests > 0 {
+		stats += fmt.Sprintf(" · %d %s", requests, plural(requests, "request", "requests"))
+	}
+	at := ""
+	if first := t.messages[0].at; !first.IsZero() {
+		at = first.Format("15:04") + " "
+	}
+	title := strings.Join(strings.Fields(t.title()), " ")
+	line := fmt.Sprintf("%s %s%s", arrow, at, truncate(title, max(10, width-len(stats)-12)))
+	if current {
+		return agentStyle.Render(line) + statusStyle.Render("  "+stats)
+	}
+	return line + statusStyle.Render("  "+stats)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// renderConversation draws an agent's threads and returns the line each threaded header starts on
+func (m *model) renderConversation(agentIndex int, width int) (string, map[string]int) {
+	agent := m.agents[agentIndex]
+	current := m.threadCursor[agent.id]
+	offsets := make(map[string]int)
+
+	var parts []string
+	line := 0
+	for _, t := range groupThreads(agent.messages) {
+		var part string
+		if t.id == "" {
+			part = renderMessages(t.messages, m.renderer)
+		} else {
+			offsets[t.id] = line
+			collapsed := m.collapsedThreads[t.id]
+			part = t.header(collapsed, t.id == current, width)
+			if !collapsed {
+				body := renderMessages(t.messages, m.renderer)
+				bar := statusStyle.Render("│ ")
+				part += "\n" + bar + strings.ReplaceAll(body, "\n", "\n"+bar)
+			}
+		}
+		parts = append(parts, part)
+		line += strings.Count(part, "\n") + 2
+	}
+	return strings.Join(parts, "\n\n"), offsets
+}
+
+// refreshConversation re-renders an agent's viewport, optionally following the newest message
+func (m *model) refreshConversation(agentIndex int, bottom bool) {
+	agent := m.agents[agentIndex]
+	vp := m.viewports[agent.id]
+	content, _ := m.renderConversation(agentIndex, vp.Width)
+	vp.SetContent(content)
+	if bottom {
+		vp.GotoBottom()
+	}
+	m.viewports[agent.id] = vp
+}
+
+// threadPeerActivity adds a correlated peer message to the thread it belongs to, for both agents involved
+func (m *model) threadPeerActivity(activity backgroundActivity) {
+	if activity.correlationID == "" {
+		return
+	}
+	for i, agent := range m.agents {
+		if agent.id != activity.from && agent.id != activity.to {
+			continue
+		}
+		if !containsString(m.threadIDs(i), activity.correlationID) {
+			continue
+		}
+		to := "*"
+		if activity.to != "" {
+			to = m.agentDisplayName(activity.to)
+		}
+		m.agents[i].messages = append(m.agents[i].messages, message{
+			content:       fmt.Sprintf("↔ %s → %s %s: %s", m.agentDisplayName(activity.from), to, activity.kind, activity.content),
+			isAgent:       true,
+			at:            activity.at,
+			correlationID: activity.correlationID,
+			causationID:   activity.causationID,
+			kind:          kindPeer,
+		})
+		m.refreshConversation(i, i == m.activeAgent)
+	}
+}
+
+// threadIDs lists an agent's threads that carry a correlation ID, oldest first
+func (m *model) threadIDs(agentIndex int) []string {
+	var ids []string
+	for _, t := range groupThreads(m.agents[agentIndex].messages) {
+		if t.id != "" {
+			ids = append(ids, t.id)
+		}
+	}
+	return ids
+}
+
+// jumpToThread makes a thread current and scrolls its header to the top of the viewport
+func (m *model) jumpToThread(agentIndex int, id string) {
+	agent := m.agents[agentIndex]
+	m.threadCursor[agent.id] = id
+	m.refreshConversation(agentIndex, false)
+	vp := m.viewports[agent.id]
+	_, offsets := m.renderConversation(agentIndex, vp.Width)
+	vp.SetYOffset(offsets[id])
+	m.viewports[agent.id] = vp
+}
+
+// stepThread moves the current thread by delta, starting from the newest
+func (m *model) stepThread(delta int) {
+	ids := m.threadIDs(m.activeAgent)
+	if len(ids) == 0 {
+		return
+	}
+	i := len(ids)
+	for j, id := range ids {
+		if id == m.threadCursor[m.agents[m.activeAgent].id] {
+			i = j
+		}
+	}
+	i = max(0, min(len(ids)-1, i+delta))
+	m.jumpToThread(m.activeAgent, ids[i])
+}
+
+// toggleThread collapses or expands the current thread, or the newest one if none is current
+func (m *model) toggleThread() {
+	ids := m.threadIDs(m.activeAgent)
+	if len(ids) == 0 {
+		return
+	}
+	id := m.threadCursor[m.agents[m.activeAgent].id]
+	if id == "" {
+		id = ids[len(ids)-1]
+	}
+	m.collapsedThreads[id] = !m.collapsedThreads[id]
+	m.jumpToThread(m.activeAgent, id)
+}
+
+// threadNavigator lists the active agent's threads for jumping and collapsing
+type threadNavigator struct {
+	open   bool
+	cursor int
+}
+
+func (m *model) openThreadNavigator() {
+	ids := m.threadIDs(m.activeAgent)
+	m.threadNav.open = true
+	m.threadNav.cursor = max(0, len(ids)-1)
+	for i, id := range ids {
+		if id == m.threadCursor[m.agents[m.activeAgent].id] {
+			m.threadNav.cursor = i
+		}
+	}
+}
+
+func (m *model) handleThreadNavKey(msg tea.KeyMsg) {
+	ids := m.threadIDs(m.activeAgent)
+	nav := &m.threadNav
+	switch msg.String() {
+	case "esc", "ctrl+t":
+		nav.open = false
+	case "up", "k":
+		nav.cursor = max(0, nav.cursor-1)
+	case "down", "j":
+		nav.cursor = min(max(0, len(ids)-1), nav.cursor+1)
+	case "enter":
+		if nav.cursor < len(ids) {
+			m.jumpToThread(m.activeAgent, ids[nav.cursor])
+		}
+		nav.open = false
+	case " ", "c":
+		if nav.cursor < len(ids) {
+			m.collapsedThreads[ids[nav.cursor]] = !m.collapsedThreads[ids[nav.cursor]]
+			m.refreshConversation(m.activeAgent, false)
+		}
+	case "C":
+		// Collapse everything, or expand everything if all are already collapsed
+		collapse := false
+		for _, id := range ids {
+			if !m.collapsedThreads[id] {
+				collapse = true
+			}
+		}
+		for _, id := range ids {
+			m.collapsedThreads[id] = collapse
+		}
+		m.refreshConversation(m.activeAgent, false)
+	}
+}
+
+func (m *model) renderThreadNavigator(width, height int) string {
+	agent := m.agents[m.activeAgent]
+	var threads []thread
+	for _, t := range groupThreads(agent.messages) {
+		if t.id != "" {
+			threads = append(threads, t)
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(agentStyle.Render("🧵 Threads") + " " + statusStyle.Render(fmt.Sprintf("%s · %d", agent.name, len(threads))) + "\n\n")
+	if len(threads) == 0 {
+		content.WriteString(statusStyle.Render("No threaded conversations yet") + "\n")
+	}
+	rows := max(1, height-6)
+	start := max(0, min(m.threadNav.cursor-rows+1, len(threads)-rows))
+	for i := start; i < min(len(threads), start+rows); i++ {
+		cursor := "  "
+		if i == m.threadNav.cursor {
+			cursor = "▸ "
+		}
+		t := threads[i]
+		content.WriteString(cursor + t.header(m.collapsedThreads[t.id], t.id == m.threadCursor[agent.id], width-4) + "\n")
+	}
+	content.WriteString("\n" + statusStyle.Render("↑/↓: select • Enter: jump • Space: collapse • C: collapse/expand all • Esc: close"))
+	return content.String()
+}
+
+// newCorrelationID starts a thread for a prompt typed in the TUI
+func newCorrelationID() string {
+	return fmt.Sprintf("prompt-%d", time.Now().UnixNano())
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Messages kept per agent pair for the detail list. Volume is counted separately, per second,
+// so counts stay exact over the largest window.
+const maxPairMessages = 200
+
+// Busiest edges listed beside the graph; the cursor selects among these
+const topologyListedEdges = 8
+
+var (
+	selectedEdgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("213"))
+
+	heavyEdgeStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196"))
+)
+
+type commMessage struct {
+	at      time.Time
+	from    string
+	to      string
+	kind    string
+	content string
+}
+
+// commEdge is an undirected agent pair with its traffic inside the current window
+type commEdge struct {
+	a, b     string
+	count    int
+	messages []commMessage
+}
+
+// commVolume counts the messages between a pair within one second
+type commVolume struct {
+	second int64
+	count  int
+}
+
+type commPair struct {
+	messages []commMessage
+	volume   []commVolume // oldest first, covering the largest window
+}
+
+// commGraph accumulates peer-to-peer traffic observed in the event stream
+type commGraph struct {
+	pairs map[[2]string]*commPair
+}
+
+func newCommGraph() *commGraph {
+	return &commGraph{pairs: make(map[[2]string]*commPair)}
+}
+
+// Endpoints that are not agents talking to each other
+var nonPeerEndpoints = map[string]bool{
+	"":               true,
+	"system":         true,
+	"broadcast":      true,
+	"control-center": true,
+}
+
+func pairKey(from, to string) [2]string {
+	if from > to {
+		from, to = to, from
+	}
+	return [2]string{from, to}
+}
+
+func (g *commGraph) record(event EventInfo) {
+	if nonPeerEndpoints[event.From] || nonPeerEndpoints[event.To] || event.From == event.To {
+		return
+	}
+	at := event.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	key := pairKey(event.From, event.To)
+	pair := g.pairs[key]
+	if pair == nil {
+		pair = &commPair{}
+		g.pairs[key] = pair
+	}
+	pair.messages = append(pair.messages, commMessage{
+		at:      at,
+		from:    event.From,
+		to:      event.To,
+		kind:    event.Type,
+		content: event.Message,
+	})
+	if len(pair.messages) > maxPairMessages {
+		pair.messages = pair.messages[len(pair.messages)-maxPairMessages:]
+	}
+
+	second := at.Unix()
+	if n := len(pair.volume); n > 0 && pair.volume[n-1].second == second {
+		pair.volume[n-1].count++
+	} else {
+		pair.volume = append(pair.volume, commVolume{second: second, count: 1})
+	}
+	cutoff := at.Add(-historyRetention).Unix()
+	drop := 0
+	for drop < len(pair.volume) && pair.volume[drop].second < cutoff {
+		drop++
+	}
+	pair.volume = pair.volume[drop:]
+}
+
+// edges returns pairs with traffic inside the window, busiest first
+func (g *commGraph) edges(now time.Time, window time.Duration) []commEdge {
+	cutoff := now.Add(-window)
+	var edges []commEdge
+	for key, pair := range g.pairs {
+		edge := commEdge{a: key[0], b: key[1]}
+		for _, msg := range pair.messages {
+			if !msg.at.Before(cutoff) {
+				edge.messages = append(edge.messages, msg)
+			}
+		}
+		for _, v := range pair.volume {
+			if v.second >= cutoff.Unix() {
+				edge.count += v.count
+			}
+		}
+		if edge.count > 0 {
+			edges = append(edges, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].count != edges[j].count {
+			return edges[i].count > edges[j].count
+		}
+		return edges[i].a+edges[i].b < edges[j].a+edges[j].b
+	})
+	return edges
+}
+
+// edgeGlyph picks a line character for the overall direction of an edge
+func edgeGlyph(dx, dy float64, heavy bool) rune {
+	switch {
+	case math.Abs(dy)*2 < math.Abs(dx)/2:
+		if heavy {
+			return '━'
+		}
+		return '─'
+	case math.Abs(dx) < math.Abs(dy):
+		if heavy {
+			return '┃'
+		}
+		return '│'
+	case (dx > 0) == (dy > 0):
+		return '╲'
+	default:
+		return '╱'
+	}
+}
+
+// renderTopology draws agents on an ellipse with edges weighted by message volume
+func renderTopology(agents []AgentInfo, edges []commEdge, selected int, width, height int) string {
+	names := map[string]bool{}
+	for _, edge := range edges {
+		names[edge.a] = true
+		names[edge.b] = true
+	}
+	status := map[string]string{}
+	for _, agent := range agents {
+		names[agent.Name] = true
+		status[agent.Name] = agent.Status
+	}
+	if len(names) == 0 {
+		return statusStyle.Render("No agents or peer traffic observed yet")
+	}
+	nodes := make([]string, 0, len(names))
+	for name := range names {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+
+	canvas := newDAGCanvas(width, height)
+	cx, cy := float64(width)/2, float64(height)/2
+	rx, ry := math.Max(cx-10, 1), math.Max(cy-1, 1)
+	pos := map[string][2]int{}
+	for i, name := range nodes {
+		angle := 2*math.Pi*float64(i)/float64(len(nodes)) - math.Pi/2
+		pos[name] = [2]int{int(cx + rx*math.Cos(angle)), int(cy + ry*math.Sin(angle))}
+	}
+
+	peak := 1
+	for _, edge := range edges {
+		peak = max(peak, edge.count)
+	}
+
+	// Draw the selected edge last so it stays visible where edges cross
+	order := make([]int, 0, len(edges))
+	for i := range edges {
+		if i != selected {
+			order = append(order, i)
+		}
+	}
+	if selected >= 0 && selected < len(edges) {
+		order = append(order, selected)
+	}
+
+	for _, i := range order {
+		edge := edges[i]
+		from, to := pos[edge.a], pos[edge.b]
+		ratio := float64(edge.count) / float64(peak)
+		style, heavy := &edgeStyle, false
+		switch {
+		case i == selected:
+			style, heavy = &selectedEdgeStyle, ratio >= 0.66
+		case ratio >= 0.66:
+			style, heavy = &heavyEdgeStyle, true
+		case ratio >= 0.33:
+			style = &busyStyle
+		}
+		dx, dy := float64(to[0]-from[0]), float64(to[1]-from[1])
+		glyph := string(edgeGlyph(dx, dy, heavy))
+		if ratio < 0.33 && i != selected {
+			glyph = "·"
+		}
+		steps := int(math.Max(math.Abs(dx), math.Abs(dy)))
+		for s := 1; s < steps; s++ {
+			x := from[0] + int(math.Round(dx*float64(s)/float64(steps)))
+			y := from[1] + int(math.Round(dy*float64(s)/float64(steps)))
+			canvas.text(x, y, glyph, style)
+		}
+	}
+
+	for _, name := range nodes {
+		p := pos[name]
+		label := "● " + truncate(name, 14)
+		style := stateStyle(status[name])
+		if status[name] == "" {
+			style = statValueStyle
+		}
+		canvas.text(p[0]-len([]rune(label))/2, p[1], label, &style)
+	}
+
+	return canvas.render(0, 0, width, height)
+}
+
+func (m *controlCenterModel) renderTopologyTab() string {
+	title := titleStyle.Render("🔗 Agent Topology")
+	now := time.Now()
+	window := chartWindows[m.topologyWindow]
+	edges := m.topology.edges(now, window.duration)
+	m.topologyCursor = min(m.topologyCursor, max(0, min(len(edges), topologyListedEdges)-1))
+
+	contentWidth := m.width - 8
+	listWidth := max(0, min(48, contentWidth/2))
+	graphWidth := max(contentWidth-listWidth-2, 0)
+	graphHeight := max(6, m.height-14)
+
+	// Too narrow for the graph: show the traffic list alone
+	graph := ""
+	if graphWidth >= 10 {
+		graph = renderTopology(m.liveAgents(now), edges, m.topologyCursor, graphWidth, graphHeight)
+	}
+
+	var side strings.Builder
+	side.WriteString(fmt.Sprintf("%s %s\n\n", statLabelStyle.Render("Traffic, last"), statValueStyle.Render(window.name)))
+	if len(edges) == 0 {
+		side.WriteString(statusStyle.Render("No peer messages in this window") + "\n")
+	}
+	for i, edge := range edges[:min(len(edges), topologyListedEdges)] {
+		cursor := "  "
+		if i == m.topologyCursor {
+			cursor = "▸ "
+		}
+		side.WriteString(fmt.Sprintf("%s%s ⇄ %s %s\n",
+			cursor,
+			truncate(edge.a, 14),
+			truncate(edge.b, 14),
+			statusStyle.Render(fmt.Sprintf("%d msgs", edge.count)),
+		))
+	}
+
+	if m.topologyCursor < len(edges) {
+		edge := edges[m.topologyCursor]
+		side.WriteString("\n" + statLabelStyle.Render("Recent messages") + "\n")
+		recent := edge.messages[max(0, len(edge.messages)-10):]
+		for i := len(recent) - 1; i >= 0; i-- {
+			msg := recent[i]
+			line := fmt.Sprintf("%s %s→%s [%s] %s", msg.at.Format("15:04:05"), msg.from, msg.to, msg.kind, msg.content)
+			side.WriteString(truncate(line, listWidth) + "\n")
+		}
+	}
+
+	body := lipgloss.NewStyle().Width(listWidth).Render(side.String())
+	if graph != "" {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(graphWidth+2).Render(graph), body)
+	}
+	help := statusStyle.Render("↑/↓: select edge • w: window")
+
+	return m.frameStyle().
+		Width(m.width - 4).
+		Height(m.height - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", body, help))
+}
+
+func (m *controlCenterModel) handleTopologyKey(key string) {
+	switch key {
+	case "up", "k":
+		m.topologyCursor = max(0, m.topologyCursor-1)
+	case "down", "j":
+		edges := m.topology.edges(time.Now(), chartWindows[m.topologyWindow].duration)
+		m.topologyCursor = min(max(0, min(len(edges), topologyListedEdges)-1), m.topologyCursor+1)
+	case "w":
+		m.topologyWindow = (m.topologyWindow + 1) % len(chartWindows)
+		m.topologyCursor = 0
+	}
+}
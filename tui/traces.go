@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	maxTraces         = 200
+	maxTraceEvents    = 500
+	defaultTraceSlow  = 5 * time.Second
+	traceLabelPercent = 40 // share of the waterfall width given to the event labels
+)
+
+// trace groups events by correlation ID the way Happen's observability/tracer.ts does
+type trace struct {
+	ID         string
+	Path       []string // senders in the order they joined, consecutive repeats collapsed
+	Events     []EventInfo
+	Start      time.Time
+	LastUpdate time.Time
+}
+
+// traceStore builds traces incrementally from the event stream
+type traceStore struct {
+	byID  map[string]*trace // trace ID, aliased trace IDs and event IDs
+	order []*trace          // newest first
+	slow  time.Duration
+}
+
+func newTraceStore(slow time.Duration) *traceStore {
+	return &traceStore{byID: make(map[string]*trace), slow: slow}
+}
+
+// add files an event under its trace. Like tracer.ts the trace ID is the correlation ID,
+// falling back to the event ID, and an unknown trace joins its causation parent's trace.
+// Event IDs are aliased too, so a causationId naming an event inside a correlated trace resolves.
+func (s *traceStore) add(event EventInfo) {
+	traceID := event.CorrelationID
+	if traceID == "" {
+		traceID = event.ID
+	}
+	if traceID == "" {
+		return
+	}
+
+	entry := s.byID[traceID]
+	if entry == nil && event.CausationID != "" {
+		if entry = s.byID[event.CausationID]; entry != nil {
+			s.byID[traceID] = entry
+		}
+	}
+
+	sender := event.From
+	if sender == "" {
+		sender = "unknown"
+	}
+	if entry == nil {
+		entry = &trace{ID: traceID, Path: []string{sender}, Start: event.At, LastUpdate: event.At}
+		s.byID[traceID] = entry
+		s.order = append([]*trace{entry}, s.order...)
+		s.evict()
+	} else {
+		if entry.Path[len(entry.Path)-1] != sender {
+			entry.Path = append(entry.Path, sender)
+		}
+		if event.At.Before(entry.Start) {
+			entry.Start = event.At
+		}
+		if event.At.After(entry.LastUpdate) {
+			entry.LastUpdate = event.At
+		}
+	}
+	if len(entry.Events) < maxTraceEvents {
+		entry.Events = append(entry.Events, event)
+	}
+	if event.ID != "" {
+		s.byID[event.ID] = entry
+	}
+}
+
+func (s *traceStore) evict() {
+	for len(s.order) > maxTraces {
+		oldest := s.order[len(s.order)-1]
+		s.order = s.order[:len(s.order)-1]
+		for id, t := range s.byID {
+			if t == oldest {
+				delete(s.byID, id)
+			}
+		}
+	}
+}
+
+// traceSpan is one waterfall row
+type traceSpan struct {
+	event EventInfo
+	depth int
+	end   time.Time // own duration when reported, otherwise the last event it caused
+}
+
+func (sp traceSpan) duration() time.Duration {
+	return sp.end.Sub(sp.event.At)
+}
+
+// spans orders a trace depth-first along causation links, children by time
+func (t *trace) spans() []traceSpan {
+	events := append([]EventInfo(nil), t.Events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+
+	byID := make(map[string]int)
+	for i, e := range events {
+		if e.ID != "" {
+			byID[e.ID] = i
+		}
+	}
+	children := make(map[int][]int)
+	var roots []int
+	for i, e := range events {
+		if parent, ok := byID[e.CausationID]; ok && parent != i {
+			children[parent] = append(children[parent], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	var spans []traceSpan
+	visited := make(map[int]bool)
+	var visit func(i, depth int) time.Time
+	visit = func(i, depth int) time.Time {
+		visited[i] = true
+		e := events[i]
+		end := e.At
+		if e.Duration > 0 {
+			end = e.At.Add(e.Duration)
+		}
+		index := len(spans)
+		spans = append(spans, traceSpan{event: e, depth: depth})
+		for _, child := range children[i] {
+			if !visited[child] {
+				if childEnd := visit(child, depth+1); e.Duration == 0 && childEnd.After(end) {
+					end = childEnd
+				}
+			}
+		}
+		spans[index].end = end
+		return end
+	}
+	for _, root := range roots {
+		visit(root, 0)
+	}
+	// A causation cycle has no root; start from its earliest event so it still shows
+	for i := range events {
+		if !visited[i] {
+			visit(i, 0)
+		}
+	}
+	return spans
+}
+
+// summary returns a trace's total duration, and whether any span failed or ran slow
+func (s *traceStore) summary(t *trace) (total time.Duration, failed, slow bool) {
+	for _, sp := range t.spans() {
+		total = max(total, sp.end.Sub(t.Start))
+		failed = failed || sp.event.Failed
+		slow = slow || sp.duration() >= s.slow
+	}
+	return total, failed, slow
+}
+
+func (m *controlCenterModel) renderTracesTab() string {
+	if m.openTrace != nil {
+		return m.renderWaterfall(m.openTrace)
+	}
+
+	title := titleStyle.Render("🧭 Traces")
+	width := m.width - 8
+	var content strings.Builder
+	content.WriteString(statLabelStyle.Render(fmt.Sprintf("%d traces • slow spans ≥ %s", len(m.traces.order), formatAge(m.traces.slow))) + "\n\n")
+	if len(m.traces.order) == 0 {
+		content.WriteString(statusStyle.Render("No traces yet: events need a correlationId or id to be traced") + "\n")
+	}
+
+	header := fmt.Sprintf("  %-20s %-22s %6s %9s  %s", "Trace", "Root", "Events", "Duration", "Path")
+	content.WriteString(statLabelStyle.Render(truncate(header, width)) + "\n")
+	rows := max(1, m.height-14)
+	start := max(0, min(m.traceCursor-rows+1, len(m.traces.order)-rows))
+	for i := start; i < min(len(m.traces.order), start+rows); i++ {
+		t := m.traces.order[i]
+		total, failed, slow := m.traces.summary(t)
+		cursor := "  "
+		if i == m.traceCursor {
+			cursor = "▸ "
+		}
+		mark := ""
+		switch {
+		case failed:
+			mark = offlineStyle.Render(" ✗ failed")
+		case slow:
+			mark = busyStyle.Render(" 🐢 slow")
+		}
+		line := fmt.Sprintf("%-20s %-22s %6d %9s  %s",
+			truncate(t.ID, 20),
+			truncate(t.Events[0].Type, 22),
+			len(t.Events),
+			formatDuration(total),
+			strings.Join(t.Path, " → "),
+		)
+		content.WriteString(cursor + truncate(line, width-lipgloss.Width(mark)-2) + mark + "\n")
+	}
+
+	help := statusStyle.Render("↑/↓: select • Enter: waterfall")
+	return m.frameStyle().
+		Width(m.width - 4).
+		Height(m.height - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", content.String(), help))
+}
+
+// renderWaterfall draws one row per event, indented by causation depth, with a bar placed
+// by its offset from the trace start and sized by its duration
+func (m *controlCenterModel) renderWaterfall(t *trace) string {
+	spans := t.spans()
+	total, _, _ := m.traces.summary(t)
+
+	width := m.width - 8
+	labelWidth := width * traceLabelPercent / 100
+	barWidth := max(10, width-labelWidth-12)
+	scale := func(d time.Duration) int {
+		if total <= 0 {
+			return 0
+		}
+		return int(float64(d) / float64(total) * float64(barWidth))
+	}
+
+	title := titleStyle.Render("🧭 Trace " + t.ID)
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s %s  %s %s  %s %s\n",
+		statLabelStyle.Render("Total"), statValueStyle.Render(formatDuration(total)),
+		statLabelStyle.Render("Events"), statValueStyle.Render(fmt.Sprint(len(spans))),
+		statLabelStyle.Render("Path"), strings.Join(t.Path, " → "),
+	))
+	axis := fmt.Sprintf("%-*s %s%s", labelWidth, "", "0", strings.Repeat(" ", max(0, barWidth-1-len(formatDuration(total))))+formatDuration(total))
+	content.WriteString(statusStyle.Render(axis) + "\n")
+
+	rows := max(1, m.height-18)
+	start := max(0, min(m.spanCursor-rows+1, len(spans)-rows))
+	for i := start; i < min(len(spans), start+rows); i++ {
+		sp := spans[i]
+		cursor := " "
+		if i == m.spanCursor {
+			cursor = "▸"
+		}
+		label := fmt.Sprintf("%s%s %s", strings.Repeat("  ", sp.depth), sp.event.Type, sp.event.From)
+		label = cursor + truncate(label, labelWidth-1)
+		label += strings.Repeat(" ", max(0, labelWidth-lipgloss.Width(label)))
+
+		offset := min(barWidth-1, scale(sp.event.At.Sub(t.Start)))
+		length := max(1, min(barWidth-offset, scale(sp.duration())))
+		bar := strings.Repeat("█", length)
+		if sp.duration() == 0 {
+			bar = "▏"
+		}
+		style := onlineStyle
+		switch {
+		case sp.event.Failed:
+			style = offlineStyle
+		case sp.duration() >= m.traces.slow:
+			style = busyStyle
+		}
+		timing := ""
+		if sp.duration() > 0 {
+			timing = formatDuration(sp.duration())
+		}
+		if sp.event.Failed {
+			timing = strings.TrimSpace(timing + " ✗ failed")
+		}
+		content.WriteString(fmt.Sprintf("%s %s%s %s\n",
+			label,
+			strings.Repeat(" ", offset),
+			style.Render(bar),
+			strings.Repeat(" ", max(0, barWidth-offset-lipgloss.Width(bar)))+style.Render(timing),
+		))
+	}
+
+	if m.spanCursor < len(spans) {
+		e := spans[m.spanCursor].event
+		content.WriteString("\n" + statLabelStyle.Render("Selected") + fmt.Sprintf(" %s %s → %s  +%s\n",
+			e.Type, e.From, e.To, formatDuration(e.At.Sub(t.Start))))
+		if len(e.Path) > 0 {
+			content.WriteString(statusStyle.Render("  path: "+strings.Join(e.Path, " → ")) + "\n")
+		}
+		content.WriteString(statusStyle.Render("  "+truncate(e.Message, width-2)) + "\n")
+	}
+
+	help := statusStyle.Render("↑/↓: select span • Esc: back to traces")
+	return m.frameStyle().
+		Width(m.width - 4).
+		Height(m.height - 6).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", content.String(), help))
+}
+
+func (m *controlCenterModel) handleTraceKey(key string) {
+	if m.openTrace != nil {
+		switch key {
+		case "esc", "backspace":
+			m.openTrace = nil
+		case "up", "k":
+			m.spanCursor = max(0, m.spanCursor-1)
+		case "down", "j":
+			m.spanCursor = min(max(0, len(m.openTrace.spans())-1), m.spanCursor+1)
+		}
+		return
+	}
+	switch key {
+	case "up", "k":
+		m.traceCursor = max(0, m.traceCursor-1)
+	case "down", "j":
+		m.traceCursor = min(max(0, len(m.traces.order)-1), m.traceCursor+1)
+	case "enter":
+		if m.traceCursor < len(m.traces.order) {
+			m.openTrace = m.traces.order[m.traceCursor]
+			m.spanCursor = 0
+		}
+	}
+}
@@ -107,6 +107,10 @@ func (c *WSClient) Send(msgType string, payload interface{}) error {
 	}
 }
 
+func (c *WSClient) Receive() <-chan WSMessage {
+	return c.receive
+}
+
 func (c *WSClient) On(msgType string, handler func(interface{})) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the workflow graph
+var (
+	edgeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240"))
+
+	criticalEdgeStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("99"))
+)
+
+// Zoom levels control how much of each step is drawn
+const (
+	zoomCompact = iota
+	zoomNormal
+	zoomDetailed
+)
+
+// dagView holds the pan/zoom state of the workflow graph
+type dagView struct {
+	offsetX      int
+	offsetY      int
+	zoom         int
+	showCritical bool
+}
+
+func newDAGView() dagView {
+	return dagView{zoom: zoomNormal, showCritical: true}
+}
+
+func (v *dagView) pan(dx, dy int) {
+	v.offsetX = max(0, v.offsetX+dx)
+	v.offsetY = max(0, v.offsetY+dy)
+}
+
+// clamp keeps the window of width x height inside a canvas of the given size
+func (v *dagView) clamp(canvasWidth, canvasHeight, width, height int) {
+	v.offsetX = max(0, min(v.offsetX, canvasWidth-width))
+	v.offsetY = max(0, min(v.offsetY, canvasHeight-height))
+}
+
+func (v *dagView) zoomIn() {
+	if v.zoom < zoomDetailed {
+		v.zoom++
+	}
+}
+
+func (v *dagView) zoomOut() {
+	if v.zoom > zoomCompact {
+		v.zoom--
+	}
+}
+
+// dagLayout places every step of a workflow on a grid of layers
+type dagLayout struct {
+	steps    []WorkflowStep
+	index    map[string]int
+	layer    []int
+	row      []int
+	critical map[string]bool
+	path     []string
+}
+
+func layoutWorkflow(workflow WorkflowInfo) dagLayout {
+	l := dagLayout{
+		steps: workflow.Steps,
+		index: make(map[string]int, len(workflow.Steps)),
+		layer: make([]int, len(workflow.Steps)),
+		row:   make([]int, len(workflow.Steps)),
+	}
+	for i, step := range l.steps {
+		l.index[step.ID] = i
+	}
+
+	// Longest-path layering; the visiting guard keeps a malformed cycle from recursing forever
+	state := make([]int, len(l.steps))
+	var visit func(i int) int
+	visit = func(i int) int {
+		switch state[i] {
+		case 1:
+			return 0
+		case 2:
+			return l.layer[i]
+		}
+		state[i] = 1
+		layer := 0
+		for _, dep := range l.steps[i].DependsOn {
+			if j, ok := l.index[dep]; ok {
+				layer = max(layer, visit(j)+1)
+			}
+		}
+		l.layer[i] = layer
+		state[i] = 2
+		return layer
+	}
+	for i := range l.steps {
+		visit(i)
+	}
+
+	// Order each layer by the average row of its dependencies to reduce crossings
+	layers := map[int][]int{}
+	maxLayer := 0
+	for i, layer := range l.layer {
+		layers[layer] = append(layers[layer], i)
+		maxLayer = max(maxLayer, layer)
+	}
+	for layer := 0; layer <= maxLayer; layer++ {
+		members := layers[layer]
+		weight := func(i int) float64 {
+			total, count := 0.0, 0
+			for _, dep := range l.steps[i].DependsOn {
+				if j, ok := l.index[dep]; ok {
+					total += float64(l.row[j])
+					count++
+				}
+			}
+			if count == 0 {
+				return float64(i)
+			}
+			return total / float64(count)
+		}
+		sort.SliceStable(members, func(a, b int) bool {
+			return weight(members[a]) < weight(members[b])
+		})
+		for r, i := range members {
+			l.row[i] = r
+		}
+	}
+
+	l.path = criticalPath(l.steps, l.index)
+	l.critical = make(map[string]bool, len(l.path))
+	for _, id := range l.path {
+		l.critical[id] = true
+	}
+	return l
+}
+
+// criticalPath returns the heaviest dependency chain by step duration.
+// Unstarted steps count as one second so their position in the graph still matters.
+func criticalPath(steps []WorkflowStep, index map[string]int) []string {
+	best := make([]float64, len(steps))
+	prev := make([]int, len(steps))
+	state := make([]int, len(steps))
+
+	var solve func(i int) float64
+	solve = func(i int) float64 {
+		if state[i] != 0 {
+			return best[i]
+		}
+		state[i] = 1
+		weight := max(steps[i].Duration().Seconds(), 1)
+		prev[i] = -1
+		best[i] = weight
+		for _, dep := range steps[i].DependsOn {
+			j, ok := index[dep]
+			if !ok || state[j] == 1 {
+				continue
+			}
+			if total := solve(j) + weight; total > best[i] {
+				best[i] = total
+				prev[i] = j
+			}
+		}
+		state[i] = 2
+		return best[i]
+	}
+
+	end := -1
+	for i := range steps {
+		if solve(i); end == -1 || best[i] > best[end] {
+			end = i
+		}
+	}
+
+	var path []string
+	for i := end; i != -1 && len(path) < len(steps); i = prev[i] {
+		path = append([]string{steps[i].ID}, path...)
+	}
+	return path
+}
+
+// blockingSteps lists the unfinished steps on the critical path
+func (l dagLayout) blockingSteps() []WorkflowStep {
+	var blocking []WorkflowStep
+	for _, id := range l.path {
+		step := l.steps[l.index[id]]
+		if step.State != stepDone {
+			blocking = append(blocking, step)
+		}
+	}
+	return blocking
+}
+
+// dagCanvas is a grid of runes, each tagged with the style it should render with
+type dagCanvas struct {
+	width  int
+	height int
+	cells  [][]rune
+	styles [][]*lipgloss.Style
+	lines  [][]uint8
+}
+
+// Direction bits used to merge crossing and joining edge segments
+const (
+	lineUp uint8 = 1 << iota
+	lineDown
+	lineLeft
+	lineRight
+)
+
+var lineRunes = map[uint8]rune{
+	lineLeft | lineRight:                     '─',
+	lineUp | lineDown:                        '│',
+	lineDown | lineRight:                     '╭',
+	lineDown | lineLeft:                      '╮',
+	lineUp | lineRight:                       '╰',
+	lineUp | lineLeft:                        '╯',
+	lineUp | lineDown | lineRight:            '├',
+	lineUp | lineDown | lineLeft:             '┤',
+	lineLeft | lineRight | lineDown:          '┬',
+	lineLeft | lineRight | lineUp:            '┴',
+	lineUp | lineDown | lineLeft | lineRight: '┼',
+	lineLeft:                                 '─',
+	lineRight:                                '─',
+	lineUp:                                   '│',
+	lineDown:                                 '│',
+}
+
+func newDAGCanvas(width, height int) *dagCanvas {
+	c := &dagCanvas{width: width, height: height}
+	c.cells = make([][]rune, height)
+	c.styles = make([][]*lipgloss.Style, height)
+	c.lines = make([][]uint8, height)
+	for y := range c.cells {
+		c.cells[y] = []rune(strings.Repeat(" ", width))
+		c.styles[y] = make([]*lipgloss.Style, width)
+		c.lines[y] = make([]uint8, width)
+	}
+	return c
+}
+
+func (c *dagCanvas) inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.width && y < c.height
+}
+
+func (c *dagCanvas) text(x, y int, s string, style *lipgloss.Style) {
+	for i, r := range []rune(s) {
+		if c.inside(x+i, y) {
+			c.cells[y][x+i] = r
+			c.styles[y][x+i] = style
+			c.lines[y][x+i] = 0
+		}
+	}
+}
+
+func (c *dagCanvas) line(x, y int, bits uint8, style *lipgloss.Style) {
+	if !c.inside(x, y) {
+		return
+	}
+	c.lines[y][x] |= bits
+	c.cells[y][x] = lineRunes[c.lines[y][x]]
+	if c.styles[y][x] == nil || style == &criticalEdgeStyle {
+		c.styles[y][x] = style
+	}
+}
+
+// dagPoint is a canvas cell on an edge's route
+type dagPoint struct{ x, y int }
+
+// path draws an orthogonal polyline through the points, ending in an arrow. Each cell gets
+// the directions it connects, so bends, joins and crossings merge into the right rune.
+func (c *dagCanvas) path(points []dagPoint, style *lipgloss.Style) {
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		switch {
+		case a.y == b.y && a.x < b.x:
+			c.line(a.x, a.y, lineRight, style)
+			for x := a.x + 1; x < b.x; x++ {
+				c.line(x, a.y, lineLeft|lineRight, style)
+			}
+			c.line(b.x, b.y, lineLeft, style)
+		case a.x == b.x && a.y < b.y:
+			c.line(a.x, a.y, lineDown, style)
+			for y := a.y + 1; y < b.y; y++ {
+				c.line(a.x, y, lineUp|lineDown, style)
+			}
+			c.line(b.x, b.y, lineUp, style)
+		case a.x == b.x && a.y > b.y:
+			c.line(a.x, a.y, lineUp, style)
+			for y := b.y + 1; y < a.y; y++ {
+				c.line(a.x, y, lineUp|lineDown, style)
+			}
+			c.line(b.x, b.y, lineDown, style)
+		}
+	}
+	if end := points[len(points)-1]; c.inside(end.x, end.y) {
+		c.cells[end.y][end.x] = '▶'
+		c.styles[end.y][end.x] = style
+	}
+}
+
+// render returns the window of the canvas starting at (ox,oy)
+func (c *dagCanvas) render(ox, oy, width, height int) string {
+	var out strings.Builder
+	for y := oy; y < oy+height && y < c.height; y++ {
+		var run strings.Builder
+		var runStyle *lipgloss.Style
+		flush := func() {
+			if run.Len() == 0 {
+				return
+			}
+			if runStyle != nil {
+				out.WriteString(runStyle.Render(run.String()))
+			} else {
+				out.WriteString(run.String())
+			}
+			run.Reset()
+		}
+		for x := ox; x < ox+width && x < c.width; x++ {
+			if c.styles[y][x] != runStyle {
+				flush()
+				runStyle = c.styles[y][x]
+			}
+			run.WriteRune(c.cells[y][x])
+		}
+		flush()
+		out.WriteString("\n")
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
+
+// nodeLabel renders a step at the current zoom level
+func nodeLabel(step WorkflowStep, zoom int, width int) string {
+	name := step.Name
+	if name == "" {
+		name = step.ID
+	}
+	switch zoom {
+	case zoomCompact:
+		return "●"
+	case zoomDetailed:
+		label := fmt.Sprintf("%s @%s %s", name, step.Agent, formatDuration(step.Duration()))
+		return "[" + padRight(truncate(label, width-2), width-2) + "]"
+	default:
+		return "[" + padRight(truncate(name, width-2), width-2) + "]"
+	}
+}
+
+func padRight(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// dagGeometry is the node width, the gap between layers and the lines per row at a zoom level
+func dagGeometry(zoom int) (nodeWidth, gap, rowGap int) {
+	switch zoom {
+	case zoomCompact:
+		return 1, 4, 1
+	case zoomDetailed:
+		return 30, 8, 2
+	}
+	return 16, 6, 2
+}
+
+// drawWorkflowDAG lays out a workflow and draws it onto a canvas sized to fit
+func drawWorkflowDAG(workflow WorkflowInfo, view dagView) (*dagCanvas, dagLayout) {
+	l := layoutWorkflow(workflow)
+	nodeWidth, gap, rowGap := dagGeometry(view.zoom)
+
+	layers, rows := 0, 0
+	occupied := map[dagPoint]bool{} // (layer, line) of every node
+	for i := range l.steps {
+		layers = max(layers, l.layer[i]+1)
+		rows = max(rows, l.row[i]+1)
+		occupied[dagPoint{l.layer[i], l.row[i] * rowGap}] = true
+	}
+	pos := func(i int) (int, int) {
+		return l.layer[i] * (nodeWidth + gap), l.row[i] * rowGap
+	}
+
+	// Route every edge before sizing the canvas, since a detour may run below the last row.
+	// An edge into the next layer bends in the gap before its target. One that skips layers
+	// leaves through the gap after its source and crosses on the nearest line that no node in
+	// the skipped layers sits on.
+	type route struct {
+		points []dagPoint
+		style  *lipgloss.Style
+	}
+	var routes []route
+	height := rows * rowGap
+	for i, step := range l.steps {
+		x2, y2 := pos(i)
+		for _, dep := range step.DependsOn {
+			j, ok := l.index[dep]
+			if !ok {
+				continue
+			}
+			x1, y1 := pos(j)
+			x1 += nodeWidth
+			style := &edgeStyle
+			if view.showCritical && l.critical[dep] && l.critical[step.ID] {
+				style = &criticalEdgeStyle
+			}
+			bendIn := x2 - gap/2 - 1
+			if l.layer[i] <= l.layer[j]+1 {
+				routes = append(routes, route{[]dagPoint{{x1, y1}, {bendIn, y1}, {bendIn, y2}, {x2 - 1, y2}}, style})
+				continue
+			}
+			lane := detourLane(y1, l.layer[j]+1, l.layer[i]-1, occupied)
+			height = max(height, lane+1)
+			bendOut := x1 + gap/2 - 1
+			routes = append(routes, route{[]dagPoint{
+				{x1, y1}, {bendOut, y1}, {bendOut, lane}, {bendIn, lane}, {bendIn, y2}, {x2 - 1, y2},
+			}, style})
+		}
+	}
+
+	canvas := newDAGCanvas(layers*(nodeWidth+gap), height)
+	// Edges first so nodes draw over any overlap
+	for _, r := range routes {
+		canvas.path(r.points, r.style)
+	}
+	for i, step := range l.steps {
+		x, y := pos(i)
+		style := stateStyle(step.State)
+		if view.showCritical && l.critical[step.ID] {
+			style = style.Bold(true).Underline(true)
+		}
+		canvas.text(x, y, nodeLabel(step, view.zoom, nodeWidth), &style)
+	}
+	return canvas, l
+}
+
+// detourLane finds the line nearest to y that no node occupies in layers first..last
+func detourLane(y, first, last int, occupied map[dagPoint]bool) int {
+	free := func(line int) bool {
+		for layer := first; layer <= last; layer++ {
+			if occupied[dagPoint{layer, line}] {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0; ; d++ {
+		if free(y + d) {
+			return y + d
+		}
+		if y-d >= 0 && free(y-d) {
+			return y - d
+		}
+	}
+}
+
+// renderWorkflowDAG draws the workflow graph into a width x height window
+func renderWorkflowDAG(workflow WorkflowInfo, view dagView, width, height int) string {
+	if len(workflow.Steps) == 0 {
+		return statusStyle.Render("No steps reported for this workflow yet")
+	}
+	canvas, l := drawWorkflowDAG(workflow, view)
+	graphHeight := max(1, height-2)
+	view.clamp(canvas.width, canvas.height, width, graphHeight)
+	graph := canvas.render(view.offsetX, view.offsetY, width, graphHeight)
+
+	summary := statusStyle.Render("Critical path complete")
+	if blocking := l.blockingSteps(); len(blocking) > 0 {
+		parts := make([]string, len(blocking))
+		for i, step := range blocking {
+			name := step.Name
+			if name == "" {
+				name = step.ID
+			}
+			parts[i] = stateStyle(step.State).Render(fmt.Sprintf("%s (%s, %s)", name, step.State, step.Agent))
+		}
+		summary = statLabelStyle.Render("Blocking: ") + strings.Join(parts, criticalEdgeStyle.Render(" → "))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, graph, "", summary)
+}
+
+// clampDAGView keeps the graph from panning past its edges, in the window renderWorkflowsTab gives it
+func (m *controlCenterModel) clampDAGView() {
+	workflow := m.selectedWorkflow()
+	if workflow == nil || len(workflow.Steps) == 0 {
+		return
+	}
+	canvas, _ := drawWorkflowDAG(*workflow, m.dagView)
+	m.dagView.clamp(canvas.width, canvas.height, m.width-8, max(1, m.height-12-2))
+}
+
+func (m *controlCenterModel) handleGraphKey(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "esc", "backspace":
+		m.showWorkflowGraph = false
+		m.showWorkflowDetail = false
+	case "g":
+		m.showWorkflowGraph = false
+	case "left", "h":
+		m.dagView.pan(-4, 0)
+	case "right", "l":
+		m.dagView.pan(4, 0)
+	case "up", "k":
+		m.dagView.pan(0, -2)
+	case "down", "j":
+		m.dagView.pan(0, 2)
+	case "+", "=":
+		m.dagView.zoomIn()
+	case "-", "_":
+		m.dagView.zoomOut()
+	case "c":
+		m.dagView.showCritical = !m.dagView.showCritical
+	}
+	m.clampDAGView()
+}
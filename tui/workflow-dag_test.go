@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func step(id string, seconds int, deps ...string) WorkflowStep {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return WorkflowStep{ID: id, Name: id, State: stepDone, DependsOn: deps, StartedAt: start, EndedAt: start.Add(time.Duration(seconds) * time.Second)}
+}
+
+// fetch feeds parse and enrich; report waits on both and also reads fetch directly
+var reportWorkflow = WorkflowInfo{ID: "wf", Steps: []WorkflowStep{
+	step("fetch", 2),
+	step("parse", 3, "fetch"),
+	step("enrich", 10, "fetch"),
+	step("report", 1, "parse", "enrich", "fetch"),
+}}
+
+func TestLayoutWorkflow(t *testing.T) {
+	l := layoutWorkflow(reportWorkflow)
+	if want := []int{0, 1, 1, 2}; !reflect.DeepEqual(l.layer, want) {
+		t.Errorf("layers = %v, want %v", l.layer, want)
+	}
+	if l.row[l.index["parse"]] == l.row[l.index["enrich"]] {
+		t.Error("parse and enrich share a row")
+	}
+
+	// A cycle must not hang the layout
+	cyclic := WorkflowInfo{Steps: []WorkflowStep{step("a", 1, "b"), step("b", 1, "a")}}
+	if l := layoutWorkflow(cyclic); len(l.layer) != 2 {
+		t.Errorf("cyclic layout = %+v", l)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	l := layoutWorkflow(reportWorkflow)
+	if want := []string{"fetch", "enrich", "report"}; !reflect.DeepEqual(l.path, want) {
+		t.Errorf("critical path = %v, want %v", l.path, want)
+	}
+
+	// Unstarted steps weigh a second each, so the longer chain wins
+	pending := WorkflowInfo{Steps: []WorkflowStep{
+		{ID: "a"}, {ID: "b", DependsOn: []string{"a"}}, {ID: "c", DependsOn: []string{"b"}}, {ID: "d", DependsOn: []string{"a"}},
+	}}
+	if path := layoutWorkflow(pending).path; !reflect.DeepEqual(path, []string{"a", "b", "c"}) {
+		t.Errorf("pending critical path = %v", path)
+	}
+}
+
+func TestSkippingEdgeAvoidsNodes(t *testing.T) {
+	// fetch -> report skips the layer holding parse and enrich
+	for _, zoom := range []int{zoomCompact, zoomNormal, zoomDetailed} {
+		view := dagView{zoom: zoom}
+		canvas, l := drawWorkflowDAG(reportWorkflow, view)
+		nodeWidth, gap, rowGap := dagGeometry(zoom)
+		for _, id := range []string{"parse", "enrich"} {
+			i := l.index[id]
+			x, y := l.layer[i]*(nodeWidth+gap), l.row[i]*rowGap
+			want := nodeLabel(l.steps[i], zoom, nodeWidth)
+			if got := string(canvas.cells[y][x : x+len([]rune(want))]); got != want {
+				t.Errorf("zoom %d: %s drawn as %q, want %q", zoom, id, got, want)
+			}
+			// Nothing may be drawn through the node from its left
+			if x > 0 && canvas.lines[y][x-1]&lineRight != 0 && zoom != zoomCompact {
+				t.Errorf("zoom %d: an edge runs into %s", zoom, id)
+			}
+		}
+		if arrows := strings.Count(canvas.render(0, 0, canvas.width, canvas.height), "▶"); arrows < 3 {
+			t.Errorf("zoom %d: %d arrowheads, want one per target line", zoom, arrows)
+		}
+	}
+}
+
+func TestDAGPanClamp(t *testing.T) {
+	v := newDAGView()
+	v.pan(-4, -2)
+	if v.offsetX != 0 || v.offsetY != 0 {
+		t.Errorf("panned to %d,%d before the origin", v.offsetX, v.offsetY)
+	}
+	v.pan(400, 40)
+	v.clamp(120, 10, 50, 6)
+	if v.offsetX != 70 || v.offsetY != 4 {
+		t.Errorf("offset = %d,%d, want 70,4", v.offsetX, v.offsetY)
+	}
+	v.clamp(30, 3, 50, 6) // canvas smaller than the window
+	if v.offsetX != 0 || v.offsetY != 0 {
+		t.Errorf("offset = %d,%d on a small canvas", v.offsetX, v.offsetY)
+	}
+}
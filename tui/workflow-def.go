@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkflowDefinition is a reusable multi-agent workflow loaded from a JSON file
+type WorkflowDefinition struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Inputs      map[string]string        `json:"inputs"`
+	Steps       []WorkflowStepDefinition `json:"steps"`
+}
+
+type WorkflowStepDefinition struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Role             string            `json:"role"`
+	Capability       string            `json:"capability"`
+	Task             string            `json:"task"`
+	DependsOn        []string          `json:"dependsOn"`
+	Inputs           map[string]string `json:"inputs"`
+	Outputs          []string          `json:"outputs"`
+	RequiresApproval bool              `json:"requiresApproval"`
+	ApprovalPrompt   string            `json:"approvalPrompt"`
+}
+
+// definitionError points at the place in a definition file that failed validation
+type definitionError struct {
+	File string
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (e definitionError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Col, e.Msg)
+}
+
+// References between steps look like ${steps.<id>.outputs.<name>}; ${inputs.<name>} reads workflow inputs
+var stepReference = regexp.MustCompile(`\$\{(steps\.([^.}]+)\.outputs\.([^}]+)|inputs\.([^}]+))\}`)
+
+// LoadWorkflowDefinition reads, parses and validates a definition against the known agents
+func LoadWorkflowDefinition(path string, agents []AgentInfo) (*WorkflowDefinition, []error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return ParseWorkflowDefinition(path, data, agents)
+}
+
+func ParseWorkflowDefinition(file string, data []byte, agents []AgentInfo) (*WorkflowDefinition, []error) {
+	var def WorkflowDefinition
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, []error{decodeDefinitionError(file, data, err)}
+	}
+
+	positions := indexJSONPositions(data)
+	at := func(path string, format string, args ...interface{}) error {
+		line, col := positions.lookup(data, path)
+		return definitionError{File: file, Line: line, Col: col, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	return &def, validateWorkflowDefinition(&def, agents, at)
+}
+
+func validateWorkflowDefinition(def *WorkflowDefinition, agents []AgentInfo, at func(string, string, ...interface{}) error) []error {
+	var errs []error
+
+	if strings.TrimSpace(def.Name) == "" {
+		errs = append(errs, at("name", "workflow name is required"))
+	}
+	if len(def.Steps) == 0 {
+		errs = append(errs, at("steps", "workflow has no steps"))
+	}
+
+	// Roles and capabilities are checked against the registered agents, so with none known the
+	// definition cannot be validated at all
+	if len(agents) == 0 {
+		errs = append(errs, at("steps", "cannot validate roles and capabilities: no agents registered"))
+	}
+	capabilities := map[string]bool{}
+	roles := map[string]bool{}
+	for _, agent := range agents {
+		roles[agent.Type] = true
+		for _, c := range agent.Capabilities {
+			capabilities[c] = true
+		}
+	}
+
+	ids := map[string]int{}
+	for i, step := range def.Steps {
+		path := fmt.Sprintf("steps[%d]", i)
+		switch {
+		case step.ID == "":
+			errs = append(errs, at(path, "step %d has no id", i+1))
+		case ids[step.ID] > 0:
+			errs = append(errs, at(path+".id", "duplicate step id %q", step.ID))
+		default:
+			ids[step.ID] = i + 1
+		}
+
+		if step.Role == "" && step.Capability == "" {
+			errs = append(errs, at(path, "step %q needs a role or a capability", step.ID))
+		}
+		if step.Capability != "" && len(agents) > 0 && !capabilities[step.Capability] {
+			errs = append(errs, at(path+".capability", "unknown capability %q (no registered agent offers it)", step.Capability))
+		}
+		if step.Role != "" && len(agents) > 0 && !roles[step.Role] {
+			errs = append(errs, at(path+".role", "unknown role %q (no registered agent has this type)", step.Role))
+		}
+	}
+
+	for i, step := range def.Steps {
+		path := fmt.Sprintf("steps[%d]", i)
+		for j, dep := range step.DependsOn {
+			if ids[dep] == 0 {
+				errs = append(errs, at(fmt.Sprintf("%s.dependsOn[%d]", path, j), "step %q depends on unknown step %q", step.ID, dep))
+			}
+		}
+	}
+
+	if cycle := findDefinitionCycle(def.Steps); len(cycle) > 0 {
+		errs = append(errs, at(fmt.Sprintf("steps[%d].dependsOn", ids[cycle[0]]-1), "dependency cycle: %s", strings.Join(cycle, " → ")))
+		return errs
+	}
+
+	// Input references must point at declared outputs of upstream steps
+	for i, step := range def.Steps {
+		upstream := upstreamSteps(def.Steps, ids, step.ID)
+		for _, name := range sortedKeys(step.Inputs) {
+			path := fmt.Sprintf("steps[%d].inputs.%s", i, name)
+			for _, ref := range stepReference.FindAllStringSubmatch(step.Inputs[name], -1) {
+				if ref[4] != "" {
+					if _, ok := def.Inputs[ref[4]]; !ok {
+						errs = append(errs, at(path, "unknown workflow input %q", ref[4]))
+					}
+					continue
+				}
+				from, output := ref[2], ref[3]
+				switch {
+				case ids[from] == 0:
+					errs = append(errs, at(path, "input references unknown step %q", from))
+				case !upstream[from]:
+					errs = append(errs, at(path, "input references step %q which is not upstream of %q", from, step.ID))
+				case !containsString(def.Steps[ids[from]-1].Outputs, output):
+					errs = append(errs, at(path, "step %q has no output %q", from, output))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// findDefinitionCycle returns the step ids forming a dependency cycle, closing back on the first id
+func findDefinitionCycle(steps []WorkflowStepDefinition) []string {
+	deps := map[string][]string{}
+	for _, step := range steps {
+		deps[step.ID] = step.DependsOn
+	}
+
+	state := map[string]int{}
+	var stack []string
+	var cycle []string
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		state[id] = 1
+		stack = append(stack, id)
+		for _, dep := range deps[id] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if state[dep] == 1 {
+				for i, s := range stack {
+					if s == dep {
+						cycle = append(append([]string{}, stack[i:]...), dep)
+						return true
+					}
+				}
+			}
+			if state[dep] == 0 && visit(dep) {
+				return true
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = 2
+		return false
+	}
+
+	for _, step := range steps {
+		if state[step.ID] == 0 && visit(step.ID) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+func upstreamSteps(steps []WorkflowStepDefinition, ids map[string]int, id string) map[string]bool {
+	seen := map[string]bool{}
+	var walk func(id string)
+	walk = func(id string) {
+		if ids[id] == 0 {
+			return
+		}
+		for _, dep := range steps[ids[id]-1].DependsOn {
+			if !seen[dep] {
+				seen[dep] = true
+				walk(dep)
+			}
+		}
+	}
+	walk(id)
+	return seen
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decodeDefinitionError turns encoding/json failures into file:line errors
+func decodeDefinitionError(file string, data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := lineAndColumn(data, syntaxErr.Offset)
+		return definitionError{File: file, Line: line, Col: col, Msg: syntaxErr.Error()}
+	case errors.As(err, &typeErr):
+		line, col := lineAndColumn(data, typeErr.Offset)
+		return definitionError{File: file, Line: line, Col: col, Msg: fmt.Sprintf("%s must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		offset := int64(bytes.Index(data, []byte(`"`+field+`"`)))
+		line, col := lineAndColumn(data, max(offset, 0))
+		return definitionError{File: file, Line: line, Col: col, Msg: fmt.Sprintf("unknown field %q", field)}
+	}
+	return definitionError{File: file, Msg: err.Error()}
+}
+
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// jsonPositions maps paths like steps[2].dependsOn[0] to byte offsets in the source
+type jsonPositions map[string]int64
+
+func indexJSONPositions(data []byte) jsonPositions {
+	positions := jsonPositions{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(path string, start int64) error
+	walk = func(path string, start int64) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		positions[path] = start
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		switch delim {
+		case '{':
+			for dec.More() {
+				keyStart := dec.InputOffset()
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child := fmt.Sprint(key)
+				if path != "" {
+					child = path + "." + child
+				}
+				positions[child] = keyStart
+				if err := walk(child, keyStart); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i), dec.InputOffset()); err != nil {
+					return err
+				}
+			}
+		}
+		_, err = dec.Token()
+		return err
+	}
+	walk("", 0)
+	return positions
+}
+
+// lookup finds the closest recorded position for a path, falling back to its parents
+func (p jsonPositions) lookup(data []byte, path string) (int, int) {
+	for path != "" {
+		if offset, ok := p[path]; ok {
+			// Offsets point just before the token; skip separators and whitespace
+			for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,:", rune(data[offset])) {
+				offset++
+			}
+			return lineAndColumn(data, offset)
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return 1, 1
+}
+
+// toWorkflowInfo creates the tracking entry for a submitted definition
+func (d *WorkflowDefinition) toWorkflowInfo(id string) WorkflowInfo {
+	workflow := WorkflowInfo{ID: id, Name: d.Name, Status: "submitted"}
+	for _, step := range d.Steps {
+		agent := step.Role
+		if agent == "" {
+			agent = step.Capability
+		}
+		workflow.Steps = append(workflow.Steps, WorkflowStep{
+			ID:        step.ID,
+			Name:      step.Name,
+			Agent:     agent,
+			State:     stepPending,
+			DependsOn: step.DependsOn,
+		})
+	}
+	return workflow
+}
+
+// submitWorkflowDefinition sends a validated definition to the bridge and starts tracking it
+func (m *controlCenterModel) submitWorkflowDefinition(path string) {
+	def, errs := LoadWorkflowDefinition(path, m.agents)
+	if len(errs) > 0 {
+		m.workflowErrors = make([]string, len(errs))
+		for i, err := range errs {
+			m.workflowErrors[i] = err.Error()
+		}
+		return
+	}
+	m.workflowErrors = nil
+
+	if m.wsClient == nil {
+		m.workflowErrors = []string{"submit failed: not connected to the bridge"}
+		return
+	}
+	id := fmt.Sprintf("wf-%d", time.Now().UnixMilli())
+	if err := m.wsClient.Send("workflow:submit", map[string]interface{}{
+		"id":         id,
+		"definition": def,
+	}); err != nil {
+		m.workflowErrors = []string{fmt.Sprintf("submit failed: %v", err)}
+		return
+	}
+
+	m.upsertWorkflow(def.toWorkflowInfo(id))
+	m.updateWorkflowList()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Workflow step states as reported by the bridge
+const (
+	stepPending      = "pending"
+	stepRunning      = "running"
+	stepWaitingHuman = "waiting-for-human"
+	stepDone         = "done"
+	stepFailed       = "failed"
+)
+
+type WorkflowStep struct {
+	ID        string
+	Name      string
+	Agent     string
+	State     string
+	DependsOn []string
+	StartedAt time.Time
+	EndedAt   time.Time
+}
+
+// Duration reports how long the step has been (or was) running
+func (s WorkflowStep) Duration() time.Duration {
+	if s.StartedAt.IsZero() {
+		return 0
+	}
+	if s.EndedAt.IsZero() {
+		return time.Since(s.StartedAt)
+	}
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+func (w WorkflowInfo) FilterValue() string { return w.Name }
+
+func (w WorkflowInfo) Title() string {
+	return fmt.Sprintf("%s %s", stateStyle(w.Status).Render("●"), w.Name)
+}
+
+func (w WorkflowInfo) Description() string {
+	done := 0
+	for _, step := range w.Steps {
+		if step.State == stepDone {
+			done++
+		}
+	}
+
+	agents := "no agents"
+	if len(w.Agents) > 0 {
+		agents = strings.Join(w.Agents, ", ")
+	}
+	return fmt.Sprintf("%s • %d/%d steps • %s", w.Status, done, len(w.Steps), agents)
+}
+
+// stateStyle maps workflow and step states onto the agent status palette
+func stateStyle(state string) lipgloss.Style {
+	switch state {
+	case stepDone, "completed", "online":
+		return onlineStyle
+	case stepRunning, stepWaitingHuman, "busy":
+		return busyStyle
+	case stepFailed, "offline":
+		return offlineStyle
+	default:
+		return statusStyle
+	}
+}
+
+// applyStepUpdate merges a step update into the workflow, appending unknown steps
+func (w *WorkflowInfo) applyStepUpdate(step WorkflowStep) {
+	for i := range w.Steps {
+		if w.Steps[i].ID == step.ID {
+			w.Steps[i] = step
+			w.addAgent(step.Agent)
+			return
+		}
+	}
+	w.Steps = append(w.Steps, step)
+	w.addAgent(step.Agent)
+}
+
+func (w *WorkflowInfo) addAgent(agent string) {
+	if agent == "" {
+		return
+	}
+	for _, a := range w.Agents {
+		if a == agent {
+			return
+		}
+	}
+	w.Agents = append(w.Agents, agent)
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	default:
+		return d.Truncate(time.Second).String()
+	}
+}
+
+func (m *controlCenterModel) updateWorkflowList() {
+	items := make([]list.Item, len(m.workflows))
+	for i, w := range m.workflows {
+		items[i] = w
+	}
+	m.workflowList.SetItems(items)
+}
+
+func (m *controlCenterModel) selectedWorkflow() *WorkflowInfo {
+	if len(m.workflows) == 0 {
+		return nil
+	}
+	selected, ok := m.workflowList.SelectedItem().(WorkflowInfo)
+	if !ok {
+		return nil
+	}
+	for i := range m.workflows {
+		if m.workflows[i].ID == selected.ID {
+			return &m.workflows[i]
+		}
+	}
+	return nil
+}
+
+func (m *controlCenterModel) upsertWorkflow(workflow WorkflowInfo) {
+	for i := range m.workflows {
+		if m.workflows[i].ID == workflow.ID {
+			m.workflows[i] = workflow
+			return
+		}
+	}
+	m.workflows = append(m.workflows, workflow)
+}
+
+func (m *controlCenterModel) applyWorkflowStep(workflowID string, step WorkflowStep) {
+	for i := range m.workflows {
+		if m.workflows[i].ID == workflowID {
+			m.workflows[i].applyStepUpdate(step)
+			return
+		}
+	}
+	workflow := WorkflowInfo{ID: workflowID, Name: workflowID, Status: stepRunning}
+	workflow.applyStepUpdate(step)
+	m.workflows = append(m.workflows, workflow)
+}
+
+func (m *controlCenterModel) updateWorkflowDetail() {
+	workflow := m.selectedWorkflow()
+	if workflow == nil {
+		m.workflowDetail.SetContent("No workflow selected")
+		return
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s %s\n", agentStyle.Render(workflow.Name), statusStyle.Render("("+workflow.ID+")")))
+	content.WriteString(fmt.Sprintf("%s %s\n\n", statLabelStyle.Render("Status:"), stateStyle(workflow.Status).Render(workflow.Status)))
+
+	content.WriteString(fmt.Sprintf("%-24s %-20s %-20s %10s\n", "Step", "Agent", "State", "Duration"))
+	content.WriteString(strings.Repeat("─", 77) + "\n")
+	for _, step := range workflow.Steps {
+		name := step.Name
+		if name == "" {
+			name = step.ID
+		}
+		content.WriteString(fmt.Sprintf(
+			"%-24s %-20s %s %10s\n",
+			truncate(name, 24),
+			truncate(step.Agent, 20),
+			stateStyle(step.State).Render(fmt.Sprintf("%-20s", step.State)),
+			formatDuration(step.Duration()),
+		))
+	}
+
+	m.workflowDetail.SetContent(content.String())
+}
+
+func truncate(s string, width int) string {
+	width = max(width, 0)
+	if len([]rune(s)) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string([]rune(s)[:width])
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// Workflow bridge message types
+type WorkflowUpdate struct {
+	Workflow WorkflowInfo
+}
+
+type WorkflowStepUpdate struct {
+	WorkflowID string
+	Step       WorkflowStep
+}